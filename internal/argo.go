@@ -1,54 +1,391 @@
 package internal
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"net/http"
 	"time"
 
-	"github.com/magefile/mage/sh"
+	"gopkg.in/yaml.v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
-// GetArgoCDAdminPassword retrieves the ArgoCD admin password
-func GetArgoCDAdminPassword(namespace string) (string, error) {
-	// Get the admin password from the secret
-	password, err := sh.Output("kubectl", "get", "secret", "argocd-initial-admin-secret",
-		"--namespace", namespace, "-o", "jsonpath={.data.password}")
+// ArgoCDSession is an authenticated ArgoCD API session, as returned by
+// ArgoCDClient.Login.
+type ArgoCDSession struct {
+	serverAddr string
+	token      string
+	httpClient *http.Client
+}
+
+// ArgoCDClient wraps the ArgoCD REST API so mage targets can manage
+// Applications, AppProjects, and cluster registrations directly instead of
+// shelling out to kubectl/argocd.
+type ArgoCDClient struct {
+	ServerAddr string
+	Insecure   bool
+}
+
+// NewArgoCDClient builds an ArgoCDClient targeting the given ArgoCD API
+// server address (host:port, typically reached via port-forward).
+func NewArgoCDClient(serverAddr string, insecure bool) *ArgoCDClient {
+	return &ArgoCDClient{ServerAddr: serverAddr, Insecure: insecure}
+}
+
+// kubeconfig mirrors just the fields of a kubeconfig file that are needed
+// to register a cluster with ArgoCD.
+type kubeconfig struct {
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// NamedCluster is the `cluster` half of a kubeconfig context, decoded from
+// its base64-encoded certificate-authority-data.
+type NamedCluster struct {
+	Name   string
+	Server string
+	CAData []byte
+}
+
+// NamedAuthInfo is the `user` half of a kubeconfig context, decoded from
+// its base64-encoded client certificate/key data.
+type NamedAuthInfo struct {
+	Name     string
+	CertData []byte
+	KeyData  []byte
+}
+
+// Login authenticates against the ArgoCD API using the initial admin
+// password and returns an authenticated session. This replaces the old
+// "kubectl get secret | base64 -d" GetArgoCDAdminPassword helper.
+func (c *ArgoCDClient) Login(ctx context.Context, clientset kubernetes.Interface, namespace string) (*ArgoCDSession, error) {
+	password, err := adminPassword(ctx, clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := newArgoCDHTTPClient(c.Insecure)
+
+	body, err := json.Marshal(map[string]string{"username": "admin", "password": password})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ArgoCD login request: %w", err)
+	}
+
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := doArgoCDRequest(ctx, httpClient, http.MethodPost, c.ServerAddr, "/api/v1/session", "", body, &session); err != nil {
+		return nil, fmt.Errorf("failed to log in to ArgoCD: %w", err)
+	}
+
+	return &ArgoCDSession{serverAddr: c.ServerAddr, token: session.Token, httpClient: httpClient}, nil
+}
+
+// CreateApplication creates an ArgoCD Application targeting repoURL/path at
+// the given destination server/namespace.
+func (s *ArgoCDSession) CreateApplication(ctx context.Context, name, project, repoURL, path, targetRevision, destServer, destNamespace string) error {
+	app := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"project": project,
+			"source": map[string]interface{}{
+				"repoURL":        repoURL,
+				"path":           path,
+				"targetRevision": targetRevision,
+			},
+			"destination": map[string]interface{}{
+				"server":    destServer,
+				"namespace": destNamespace,
+			},
+		},
+	}
+
+	body, err := json.Marshal(app)
+	if err != nil {
+		return fmt.Errorf("failed to marshal application '%s': %w", name, err)
+	}
+
+	if err := s.do(ctx, http.MethodPost, "/api/v1/applications", body, nil); err != nil {
+		return fmt.Errorf("failed to create application '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// SyncApplication triggers a sync of the named ArgoCD Application.
+func (s *ArgoCDSession) SyncApplication(ctx context.Context, name string) error {
+	if err := s.do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/applications/%s/sync", name), nil, nil); err != nil {
+		return fmt.Errorf("failed to sync application '%s': %w", name, err)
+	}
+	return nil
+}
+
+// ApplicationStatus is the subset of an Application's reported status
+// mage targets need to decide whether a promotion succeeded.
+type ApplicationStatus struct {
+	Sync   string `json:"sync"`
+	Health string `json:"health"`
+}
+
+// GetApplicationStatus fetches the sync and health status of the named
+// ArgoCD Application.
+func (s *ArgoCDSession) GetApplicationStatus(ctx context.Context, name string) (*ApplicationStatus, error) {
+	var app struct {
+		Status struct {
+			Sync struct {
+				Status string `json:"status"`
+			} `json:"sync"`
+			Health struct {
+				Status string `json:"status"`
+			} `json:"health"`
+		} `json:"status"`
+	}
+
+	if err := s.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/applications/%s", name), nil, &app); err != nil {
+		return nil, fmt.Errorf("failed to get status for application '%s': %w", name, err)
+	}
+
+	return &ApplicationStatus{Sync: app.Status.Sync.Status, Health: app.Status.Health.Status}, nil
+}
+
+// CreateProject creates an ArgoCD AppProject scoped to the given source
+// repositories and destinations.
+func (s *ArgoCDSession) CreateProject(ctx context.Context, name string, sourceRepos []string, destinations []map[string]string) error {
+	project := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"sourceRepos":  sourceRepos,
+			"destinations": destinations,
+		},
+	}
+
+	body, err := json.Marshal(project)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project '%s': %w", name, err)
+	}
+
+	if err := s.do(ctx, http.MethodPost, "/api/v1/projects", body, nil); err != nil {
+		return fmt.Errorf("failed to create project '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// RegisterCluster registers a cluster's kubeconfig as an ArgoCD cluster
+// destination: parse the kubeconfig YAML into a NamedCluster/NamedAuthInfo
+// pair, base64-encode the CA/cert/key, and POST the result to
+// /api/v1/clusters.
+func (s *ArgoCDSession) RegisterCluster(ctx context.Context, kubeconfigYAML []byte, name string) error {
+	cluster, authInfo, err := parseKubeconfig(kubeconfigYAML, name)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubeconfig for cluster '%s': %w", name, err)
+	}
+
+	payload := map[string]interface{}{
+		"name":   name,
+		"server": cluster.Server,
+		"config": map[string]interface{}{
+			"tlsClientConfig": map[string]interface{}{
+				"caData":   base64.StdEncoding.EncodeToString(cluster.CAData),
+				"certData": base64.StdEncoding.EncodeToString(authInfo.CertData),
+				"keyData":  base64.StdEncoding.EncodeToString(authInfo.KeyData),
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster '%s': %w", name, err)
+	}
+
+	if err := s.do(ctx, http.MethodPost, "/api/v1/clusters", body, nil); err != nil {
+		return fmt.Errorf("failed to register cluster '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// parseKubeconfig decodes the cluster/user pair referenced by the context
+// named contextName out of a kubeconfig, base64-decoding their embedded
+// CA/cert/key data. If no context matches (or the kubeconfig carries no
+// contexts section at all), it falls back to the sole cluster/user entry,
+// as produced by `kind export kubeconfig`, `k3d kubeconfig get`, and
+// Cluster API's per-cluster kubeconfig Secrets.
+func parseKubeconfig(kubeconfigYAML []byte, contextName string) (*NamedCluster, *NamedAuthInfo, error) {
+	var kc kubeconfig
+	if err := yaml.Unmarshal(kubeconfigYAML, &kc); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal kubeconfig: %w", err)
+	}
+	if len(kc.Clusters) == 0 || len(kc.Users) == 0 {
+		return nil, nil, fmt.Errorf("kubeconfig has no clusters or users")
+	}
+
+	var clusterName, userName string
+	for _, c := range kc.Contexts {
+		if c.Name == contextName {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+			break
+		}
+	}
+	if clusterName == "" && userName == "" {
+		if len(kc.Clusters) != 1 || len(kc.Users) != 1 {
+			return nil, nil, fmt.Errorf("kubeconfig has no context named '%s' and is not scoped to a single cluster", contextName)
+		}
+		clusterName, userName = kc.Clusters[0].Name, kc.Users[0].Name
+	}
+
+	clusterIdx := -1
+	for i, c := range kc.Clusters {
+		if c.Name == clusterName {
+			clusterIdx = i
+			break
+		}
+	}
+	if clusterIdx == -1 {
+		return nil, nil, fmt.Errorf("kubeconfig has no cluster named '%s'", clusterName)
+	}
+	rawCluster := kc.Clusters[clusterIdx]
+
+	userIdx := -1
+	for i, u := range kc.Users {
+		if u.Name == userName {
+			userIdx = i
+			break
+		}
+	}
+	if userIdx == -1 {
+		return nil, nil, fmt.Errorf("kubeconfig has no user named '%s'", userName)
+	}
+	rawUser := kc.Users[userIdx]
+
+	caData, err := base64.StdEncoding.DecodeString(rawCluster.Cluster.CertificateAuthorityData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode certificate-authority-data: %w", err)
+	}
+	cluster := &NamedCluster{Name: rawCluster.Name, Server: rawCluster.Cluster.Server, CAData: caData}
+
+	certData, err := base64.StdEncoding.DecodeString(rawUser.User.ClientCertificateData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode client-certificate-data: %w", err)
+	}
+	keyData, err := base64.StdEncoding.DecodeString(rawUser.User.ClientKeyData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode client-key-data: %w", err)
+	}
+	authInfo := &NamedAuthInfo{Name: rawUser.Name, CertData: certData, KeyData: keyData}
+
+	return cluster, authInfo, nil
+}
+
+// adminPassword fetches the ArgoCD initial admin password.
+func adminPassword(ctx context.Context, clientset kubernetes.Interface, namespace string) (string, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, "argocd-initial-admin-secret", metav1.GetOptions{})
 	if err != nil {
 		return "", fmt.Errorf("failed to get ArgoCD admin password: %w", err)
 	}
 
-	// Decode base64 using Go standard library
-	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(password))
+	return string(secret.Data["password"]), nil
+}
+
+// do performs an authenticated request against the ArgoCD API, decoding a
+// JSON response into out when non-nil.
+func (s *ArgoCDSession) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	return doArgoCDRequest(ctx, s.httpClient, method, s.serverAddr, path, s.token, body, out)
+}
+
+func newArgoCDHTTPClient(insecure bool) *http.Client {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return client
+}
+
+func doArgoCDRequest(ctx context.Context, httpClient *http.Client, method, serverAddr, path, token string, body []byte, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("https://%s%s", serverAddr, path), reader)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode password: %w", err)
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
 	}
 
-	return string(decoded), nil
+	return nil
 }
 
 // ArgoRolloutsExists checks if Argo Rollouts is installed by checking for the controller pod
-func ArgoRolloutsExists(namespace string) (bool, error) {
-	// Check if the argo-rollouts-controller pod exists and is running
-	output, err := sh.Output("kubectl", "get", "pods", "--namespace", namespace, "-l", "app.kubernetes.io/name=argo-rollouts", "--no-headers")
+func ArgoRolloutsExists(ctx context.Context, clientset kubernetes.Interface, namespace string) (bool, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=argo-rollouts"})
 	if err != nil {
 		return false, nil
 	}
 
-	// If we get output, it means pods exist
-	return strings.TrimSpace(output) != "", nil
+	return len(pods.Items) > 0, nil
 }
 
 // WaitForArgoRolloutsReady waits for Argo Rollouts to be ready
-func WaitForArgoRolloutsReady(namespace string) error {
+func WaitForArgoRolloutsReady(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
 	fmt.Printf("⏳ Waiting for Argo Rollouts to be ready in namespace '%s'...\n", namespace)
 
 	for i := 0; i < 60; i++ { // Wait up to 60 seconds
-		// Check if the controller pod is running
-		output, err := sh.Output("kubectl", "get", "pods", "--namespace", namespace, "-l", "app.kubernetes.io/name=argo-rollouts", "--no-headers")
-		if err == nil && strings.Contains(output, "Running") {
-			fmt.Printf("✅ Argo Rollouts is ready\n")
-			return nil
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=argo-rollouts"})
+		if err == nil {
+			for _, pod := range pods.Items {
+				if pod.Status.Phase == corev1.PodRunning {
+					fmt.Printf("✅ Argo Rollouts is ready\n")
+					return nil
+				}
+			}
 		}
 		time.Sleep(1 * time.Second)
 	}
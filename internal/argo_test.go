@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const multiContextKubeconfig = `
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: dev-user
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com
+    certificate-authority-data: ZGV2LWNh
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+    certificate-authority-data: cHJvZC1jYQ==
+users:
+- name: dev-user
+  user:
+    client-certificate-data: ZGV2LWNlcnQ=
+    client-key-data: ZGV2LWtleQ==
+- name: prod-user
+  user:
+    client-certificate-data: cHJvZC1jZXJ0
+    client-key-data: cHJvZC1rZXk=
+`
+
+const singleClusterKubeconfig = `
+clusters:
+- name: kind-kargo
+  cluster:
+    server: https://kind.example.com
+    certificate-authority-data: ZmFrZS1jYQ==
+users:
+- name: kind-kargo
+  user:
+    client-certificate-data: ZmFrZS1jZXJ0
+    client-key-data: ZmFrZS1rZXk=
+`
+
+func TestParseKubeconfig(t *testing.T) {
+	t.Run("selects the cluster/user matching the named context", func(t *testing.T) {
+		cluster, authInfo, err := parseKubeconfig([]byte(multiContextKubeconfig), "prod")
+		if err != nil {
+			t.Fatalf("parseKubeconfig returned error: %v", err)
+		}
+		if cluster.Server != "https://prod.example.com" {
+			t.Errorf("Server = %q, want %q", cluster.Server, "https://prod.example.com")
+		}
+		if string(authInfo.CertData) != "prod-cert" {
+			t.Errorf("CertData = %q, want %q", authInfo.CertData, "prod-cert")
+		}
+	})
+
+	t.Run("falls back to the sole cluster/user when no context matches", func(t *testing.T) {
+		cluster, authInfo, err := parseKubeconfig([]byte(singleClusterKubeconfig), "kind-kargo")
+		if err != nil {
+			t.Fatalf("parseKubeconfig returned error: %v", err)
+		}
+		if cluster.Server != "https://kind.example.com" {
+			t.Errorf("Server = %q, want %q", cluster.Server, "https://kind.example.com")
+		}
+		if string(authInfo.CertData) != "fake-cert" {
+			t.Errorf("CertData = %q, want %q", authInfo.CertData, "fake-cert")
+		}
+	})
+
+	t.Run("errors on an ambiguous kubeconfig with no matching context", func(t *testing.T) {
+		if _, _, err := parseKubeconfig([]byte(multiContextKubeconfig), "staging"); err == nil {
+			t.Fatal("expected an error for an unknown context with multiple clusters, got nil")
+		}
+	})
+}
+
+func TestLogin(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/session" || r.Method != http.MethodPost {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode login request: %v", err)
+		}
+		if body["password"] != "s3cr3t" {
+			t.Errorf("password = %q, want %q", body["password"], "s3cr3t")
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"token": "test-token"})
+	}))
+	defer server.Close()
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-initial-admin-secret", Namespace: "argocd"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	})
+
+	client := NewArgoCDClient(server.Listener.Addr().String(), true)
+
+	session, err := client.Login(context.Background(), clientset, "argocd")
+	if err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+	if session.token != "test-token" {
+		t.Errorf("token = %q, want %q", session.token, "test-token")
+	}
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/magefile/mage/sh"
+)
+
+const (
+	// CAPIOperatorNamespace is where the Cluster API operator itself runs.
+	CAPIOperatorNamespace = "capi-operator-system"
+	// CAPISystemNamespace hosts the Cluster API core provider.
+	CAPISystemNamespace = "capi-system"
+	// CAPDSystemNamespace hosts the Docker (CAPD) infrastructure provider.
+	CAPDSystemNamespace = "capd-system"
+	// CAPIKubeadmBootstrapSystemNamespace hosts the kubeadm bootstrap provider.
+	CAPIKubeadmBootstrapSystemNamespace = "capi-kubeadm-bootstrap-system"
+	// CAPIKubeadmControlPlaneSystemNamespace hosts the kubeadm control-plane provider.
+	CAPIKubeadmControlPlaneSystemNamespace = "capi-kubeadm-control-plane-system"
+	// CAAPHSystemNamespace hosts the Cluster API Add-on Provider for Helm.
+	CAAPHSystemNamespace = "caaph-system"
+
+	capiOperatorVersion  = "v0.15.1"
+	capiOperatorManifest = "https://github.com/kubernetes-sigs/cluster-api-operator/releases/download/" + capiOperatorVersion + "/operator-components.yaml"
+)
+
+// ApplyCoreProvider installs the Cluster API operator at capiOperatorVersion
+// and reconciles a CoreProvider custom resource for upstream Cluster API,
+// waiting for it to report Ready before returning.
+func ApplyCoreProvider() error {
+	fmt.Printf("📦 Installing Cluster API operator %s...\n", capiOperatorVersion)
+	if err := sh.Run("kubectl", "apply", "-f", capiOperatorManifest); err != nil {
+		return fmt.Errorf("failed to install Cluster API operator: %w", err)
+	}
+
+	if err := waitForCAPIOperatorReady(); err != nil {
+		return err
+	}
+
+	fmt.Println("📦 Reconciling Cluster API core provider...")
+	if err := sh.Run("kubectl", "apply", "-f", "clusters/providers/core-cluster-api.yaml"); err != nil {
+		return fmt.Errorf("failed to apply core provider: %w", err)
+	}
+
+	return waitForCAPIOperatorCondition("coreprovider", "cluster-api", CAPISystemNamespace)
+}
+
+// ApplyBootstrapProvider reconciles the kubeadm bootstrap and control-plane
+// providers, waiting for each to report Ready before moving to the next.
+func ApplyBootstrapProvider() error {
+	fmt.Println("📦 Reconciling kubeadm bootstrap provider...")
+	if err := sh.Run("kubectl", "apply", "-f", "clusters/providers/bootstrap-kubeadm.yaml"); err != nil {
+		return fmt.Errorf("failed to apply kubeadm bootstrap provider: %w", err)
+	}
+	if err := waitForCAPIOperatorCondition("bootstrapprovider", "kubeadm", CAPIKubeadmBootstrapSystemNamespace); err != nil {
+		return err
+	}
+
+	fmt.Println("📦 Reconciling kubeadm control-plane provider...")
+	if err := sh.Run("kubectl", "apply", "-f", "clusters/providers/control-plane-kubeadm.yaml"); err != nil {
+		return fmt.Errorf("failed to apply kubeadm control-plane provider: %w", err)
+	}
+	return waitForCAPIOperatorCondition("controlplaneprovider", "kubeadm", CAPIKubeadmControlPlaneSystemNamespace)
+}
+
+// ApplyInfraProvider reconciles the CAPD (Docker) infrastructure provider and
+// the Cluster API Add-on Provider for Helm, waiting for each to report Ready.
+func ApplyInfraProvider() error {
+	fmt.Println("📦 Reconciling CAPD infrastructure provider...")
+	if err := sh.Run("kubectl", "apply", "-f", "clusters/providers/infrastructure-docker.yaml"); err != nil {
+		return fmt.Errorf("failed to apply CAPD infrastructure provider: %w", err)
+	}
+	if err := waitForCAPIOperatorCondition("infrastructureprovider", "docker", CAPDSystemNamespace); err != nil {
+		return err
+	}
+
+	fmt.Println("📦 Reconciling Cluster API Add-on Provider for Helm...")
+	if err := sh.Run("kubectl", "apply", "-f", "clusters/providers/addon-helm.yaml"); err != nil {
+		return fmt.Errorf("failed to apply Cluster API Add-on Provider for Helm: %w", err)
+	}
+	return waitForCAPIOperatorCondition("addonprovider", "helm", CAAPHSystemNamespace)
+}
+
+// ListWorkloadClusters returns the names of the Cluster API workload
+// clusters in the current-context namespace of the management cluster
+// reachable through kubeconfigPath, so callers that resolved the management
+// cluster via a ClusterProvider (rather than the ambient current-context)
+// still target the right cluster. Clusters in other namespaces are not
+// listed.
+func ListWorkloadClusters(kubeconfigPath string) ([]string, error) {
+	output, err := sh.Output("kubectl", "--kubeconfig", kubeconfigPath, "get", "clusters.cluster.x-k8s.io", "-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Cluster API workload clusters: %w", err)
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Fields(output), nil
+}
+
+// WorkloadClusterKubeconfig fetches the kubeconfig Cluster API generated for
+// the named workload cluster from the `<name>-kubeconfig` Secret it
+// maintains alongside the Cluster resource, on the management cluster
+// reachable through kubeconfigPath.
+func WorkloadClusterKubeconfig(kubeconfigPath, name string) ([]byte, error) {
+	encoded, err := sh.Output("kubectl", "--kubeconfig", kubeconfigPath, "get", "secret", name+"-kubeconfig", "-o", "jsonpath={.data.value}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret for workload cluster '%s': %w", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode kubeconfig secret for workload cluster '%s': %w", name, err)
+	}
+	return data, nil
+}
+
+// waitForCAPIOperatorReady waits for the Cluster API operator's own manager
+// deployment to become available.
+func waitForCAPIOperatorReady() error {
+	fmt.Println("⏳ Waiting for Cluster API operator to be ready...")
+	if err := sh.Run("kubectl", "wait", "--for=condition=Available", "--timeout=120s",
+		"-n", CAPIOperatorNamespace, "deployment/capi-operator-controller-manager"); err != nil {
+		return fmt.Errorf("timeout waiting for Cluster API operator to be ready: %w", err)
+	}
+	fmt.Println("✅ Cluster API operator is ready")
+	return nil
+}
+
+// waitForCAPIOperatorCondition polls a provider custom resource until its
+// Ready status condition reports True.
+func waitForCAPIOperatorCondition(resource, name, namespace string) error {
+	fmt.Printf("⏳ Waiting for %s '%s' to report Ready...\n", resource, name)
+
+	jsonPath := `{.status.conditions[?(@.type=="Ready")].status}`
+	for i := 0; i < 120; i++ { // Wait up to 2 minutes
+		output, err := sh.Output("kubectl", "get", resource, name, "--namespace", namespace, "-o", "jsonpath="+jsonPath)
+		if err == nil && output == "True" {
+			fmt.Printf("✅ %s '%s' is Ready\n", resource, name)
+			return nil
+		}
+		time.Sleep(1 * time.Second)
+	}
+
+	return fmt.Errorf("timeout waiting for %s '%s' to report Ready", resource, name)
+}
@@ -0,0 +1,46 @@
+package clusterprovider
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+// configFile is the project-local config file mage targets read the
+// default cluster provider from.
+const configFile = ".kargo.yaml"
+
+// providerEnvVar overrides the configured provider without editing
+// configFile, e.g. for CI runners that always want "existing".
+const providerEnvVar = "KARGO_CLUSTER_PROVIDER"
+
+// Config is the subset of .kargo.yaml mage targets read to decide which
+// ClusterProvider to use.
+type Config struct {
+	Provider string `koanf:"provider"`
+}
+
+// LoadConfig resolves the cluster provider to use: $KARGO_CLUSTER_PROVIDER
+// takes precedence, then the "provider" key in .kargo.yaml, then "kind".
+func LoadConfig() (Config, error) {
+	cfg := Config{Provider: "kind"}
+
+	if _, err := os.Stat(configFile); err == nil {
+		k := koanf.New(".")
+		if err := k.Load(file.Provider(configFile), yaml.Parser()); err != nil {
+			return cfg, fmt.Errorf("failed to load %s: %w", configFile, err)
+		}
+		if err := k.Unmarshal("", &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse %s: %w", configFile, err)
+		}
+	}
+
+	if provider := os.Getenv(providerEnvVar); provider != "" {
+		cfg.Provider = provider
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,87 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/magefile/mage/sh"
+
+	"github.com/konflux-ci/kargo/internal"
+)
+
+// ExistingProvider adapts an already-running, externally managed cluster
+// to the ClusterProvider interface, for contributors who want to point
+// kargo at a BYO kubeconfig context instead of provisioning anything.
+type ExistingProvider struct{}
+
+// Exists reports whether a kubeconfig context named name is reachable.
+func (ExistingProvider) Exists(name string) (bool, error) {
+	if _, _, err := internal.NewKubeClient(context.Background(), name); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Create refuses to provision anything; an existing cluster must already
+// be reachable under the given context name.
+func (ExistingProvider) Create(ctx context.Context, spec ClusterSpec) error {
+	exists, err := ExistingProvider{}.Exists(spec.Name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("existing cluster provider cannot create cluster '%s': no reachable kubeconfig context with that name", spec.Name)
+	}
+	return nil
+}
+
+// Delete is unsupported: the existing provider never owns a cluster's
+// lifecycle.
+func (ExistingProvider) Delete(name string) error {
+	return fmt.Errorf("existing cluster provider does not manage the lifecycle of cluster '%s'", name)
+}
+
+// Kubeconfig extracts the context named name out of the ambient kubeconfig
+// ($KUBECONFIG or ~/.kube/config), rather than returning the whole file, so
+// a BYO kubeconfig with multiple contexts targets the right cluster.
+func (ExistingProvider) Kubeconfig(name string) ([]byte, error) {
+	path := os.Getenv("KUBECONFIG")
+	if path == "" {
+		path = os.Getenv("HOME") + "/.kube/config"
+	}
+
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig '%s': %w", path, err)
+	}
+
+	config.CurrentContext = name
+	if err := clientcmd.MinifyConfig(config); err != nil {
+		return nil, fmt.Errorf("failed to extract context '%s' from kubeconfig '%s': %w", name, path, err)
+	}
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize kubeconfig for context '%s': %w", name, err)
+	}
+	return data, nil
+}
+
+// SelectContext switches the ambient kubeconfig's current-context to name.
+// Unlike the other providers there is nothing to merge in: an existing
+// cluster's context already lives in the ambient kubeconfig.
+func (ExistingProvider) SelectContext(name string) error {
+	return sh.Run("kubectl", "config", "use-context", name)
+}
+
+// Info connects to the named kubeconfig context and summarizes it.
+func (ExistingProvider) Info(name string) (ClusterInfo, error) {
+	version, nodeCount, err := clusterVersionAndNodes(name)
+	if err != nil {
+		return ClusterInfo{}, err
+	}
+	return ClusterInfo{Name: name, Nodes: nodeCount, Version: version}, nil
+}
@@ -0,0 +1,117 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/magefile/mage/sh"
+)
+
+// K3DProvider implements ClusterProvider on top of k3d.
+type K3DProvider struct{}
+
+// Exists checks if the specified k3d cluster exists.
+func (K3DProvider) Exists(name string) (bool, error) {
+	output, err := sh.Output("k3d", "cluster", "list", name, "-o", "json")
+	if err != nil {
+		return false, nil
+	}
+	return strings.TrimSpace(output) != "" && strings.TrimSpace(output) != "[]", nil
+}
+
+// Create creates a new k3d cluster, translating multi-node topologies,
+// port mappings, and a registry mirror into k3d CLI flags.
+func (K3DProvider) Create(ctx context.Context, spec ClusterSpec) error {
+	args := []string{"cluster", "create", spec.Name, "--wait"}
+
+	if agents := spec.Nodes - 1; agents > 0 {
+		args = append(args, "--agents", fmt.Sprintf("%d", agents))
+	}
+
+	for _, pm := range spec.PortMappings {
+		port := fmt.Sprintf("%d:%d", pm.HostPort, pm.ContainerPort)
+		if pm.Protocol != "" {
+			port += "/" + strings.ToLower(pm.Protocol)
+		}
+		args = append(args, "--port", port+"@loadbalancer")
+	}
+
+	if spec.RegistryMirror != nil {
+		configPath, cleanup, err := writeK3DRegistryConfig(spec.RegistryMirror)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		args = append(args, "--registry-config", configPath)
+	}
+
+	return sh.Run("k3d", args...)
+}
+
+// Delete deletes the k3d cluster with the given name.
+func (K3DProvider) Delete(name string) error {
+	return sh.Run("k3d", "cluster", "delete", name)
+}
+
+// Kubeconfig returns the kubeconfig for the given k3d cluster.
+func (K3DProvider) Kubeconfig(name string) ([]byte, error) {
+	output, err := sh.Output("k3d", "kubeconfig", "get", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig for k3d cluster '%s': %w", name, err)
+	}
+	return []byte(output), nil
+}
+
+// SelectContext merges the k3d cluster's kubeconfig into the default
+// kubeconfig and switches current-context to it.
+func (K3DProvider) SelectContext(name string) error {
+	return sh.Run("k3d", "kubeconfig", "merge", name, "--kubeconfig-merge-default", "--kubeconfig-switch-context")
+}
+
+// Info connects to the given k3d cluster and summarizes it.
+func (K3DProvider) Info(name string) (ClusterInfo, error) {
+	version, nodeCount, err := clusterVersionAndNodes("k3d-" + name)
+	if err != nil {
+		return ClusterInfo{}, err
+	}
+	return ClusterInfo{Name: name, Nodes: nodeCount, Version: version}, nil
+}
+
+// k3dRegistryConfig mirrors k3d's registries.yaml shape.
+type k3dRegistryConfig struct {
+	Mirrors map[string]k3dMirror `yaml:"mirrors"`
+}
+
+type k3dMirror struct {
+	Endpoint []string `yaml:"endpoint"`
+}
+
+// writeK3DRegistryConfig renders mirror into a temporary k3d
+// registries.yaml file.
+func writeK3DRegistryConfig(mirror *RegistryMirror) (string, func(), error) {
+	cfg := k3dRegistryConfig{Mirrors: map[string]k3dMirror{
+		registryMirrorHost(mirror.Upstream): {Endpoint: []string{mirror.Endpoint}},
+	}}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal k3d registry config: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "k3d-registries-*.yaml")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary k3d registry config: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", nil, fmt.Errorf("failed to write temporary k3d registry config: %w", err)
+	}
+	f.Close()
+
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
@@ -0,0 +1,173 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/magefile/mage/sh"
+
+	"github.com/konflux-ci/kargo/internal"
+)
+
+// KindProvider implements ClusterProvider on top of kind, preserving the
+// mage layer's original single-cluster behavior as the default.
+type KindProvider struct{}
+
+// Exists checks if the specified kind cluster exists.
+func (KindProvider) Exists(name string) (bool, error) {
+	return internal.ClusterExists(name)
+}
+
+// Create creates a new kind cluster, generating a kind config for
+// multi-node topologies, port mappings, or a registry mirror when spec
+// asks for any of those.
+func (KindProvider) Create(ctx context.Context, spec ClusterSpec) error {
+	configPath, cleanup, err := writeKindConfig(spec)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := []string{"create", "cluster", "--name", spec.Name, "--wait", "60s"}
+	if configPath != "" {
+		args = append(args, "--config", configPath)
+	}
+
+	return sh.Run("kind", args...)
+}
+
+// Delete deletes the kind cluster with the given name.
+func (KindProvider) Delete(name string) error {
+	return internal.DeleteCluster(name)
+}
+
+// Kubeconfig returns the kubeconfig for the given kind cluster.
+func (KindProvider) Kubeconfig(name string) ([]byte, error) {
+	output, err := sh.Output("kind", "get", "kubeconfig", "--name", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig for kind cluster '%s': %w", name, err)
+	}
+	return []byte(output), nil
+}
+
+// SelectContext exports the kind cluster's kubeconfig, which merges it
+// into the ambient kubeconfig and points current-context at it.
+func (KindProvider) SelectContext(name string) error {
+	return internal.ExportKubeconfig(name)
+}
+
+// Info connects to the given kind cluster and summarizes it.
+func (KindProvider) Info(name string) (ClusterInfo, error) {
+	version, nodeCount, err := clusterVersionAndNodes(kindContext(name))
+	if err != nil {
+		return ClusterInfo{}, err
+	}
+	return ClusterInfo{Name: name, Nodes: nodeCount, Version: version}, nil
+}
+
+func kindContext(name string) string {
+	return "kind-" + name
+}
+
+// clusterVersionAndNodes connects to the given kubeconfig context and
+// returns the control plane version and node count.
+func clusterVersionAndNodes(contextName string) (string, int, error) {
+	ctx := context.Background()
+
+	clientset, _, err := internal.NewKubeClient(ctx, contextName)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return version.String(), len(nodes.Items), nil
+}
+
+// kindConfig is the subset of kind's Cluster config used to express
+// ClusterSpec as a kind config file.
+type kindConfig struct {
+	Kind                    string     `yaml:"kind"`
+	APIVersion              string     `yaml:"apiVersion"`
+	Nodes                   []kindNode `yaml:"nodes,omitempty"`
+	ContainerdConfigPatches []string   `yaml:"containerdConfigPatches,omitempty"`
+}
+
+type kindNode struct {
+	Role              string            `yaml:"role"`
+	ExtraPortMappings []kindPortMapping `yaml:"extraPortMappings,omitempty"`
+}
+
+type kindPortMapping struct {
+	ContainerPort int    `yaml:"containerPort"`
+	HostPort      int    `yaml:"hostPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+// writeKindConfig renders spec into a temporary kind config file. It
+// returns an empty path when spec describes a plain single-node cluster,
+// so callers fall back to kind's own defaults.
+func writeKindConfig(spec ClusterSpec) (string, func(), error) {
+	noop := func() {}
+
+	if spec.Nodes <= 1 && len(spec.PortMappings) == 0 && spec.RegistryMirror == nil {
+		return "", noop, nil
+	}
+
+	cfg := kindConfig{Kind: "Cluster", APIVersion: "kind.x-k8s.io/v1alpha4"}
+
+	controlPlane := kindNode{Role: "control-plane"}
+	for _, pm := range spec.PortMappings {
+		protocol := pm.Protocol
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		controlPlane.ExtraPortMappings = append(controlPlane.ExtraPortMappings, kindPortMapping{
+			ContainerPort: pm.ContainerPort,
+			HostPort:      pm.HostPort,
+			Protocol:      protocol,
+		})
+	}
+	cfg.Nodes = append(cfg.Nodes, controlPlane)
+
+	for i := 1; i < spec.Nodes; i++ {
+		cfg.Nodes = append(cfg.Nodes, kindNode{Role: "worker"})
+	}
+
+	if spec.RegistryMirror != nil {
+		cfg.ContainerdConfigPatches = []string{fmt.Sprintf(
+			"[plugins.\"io.containerd.grpc.v1.cri\".registry.mirrors.%q]\n  endpoint = [%q]",
+			registryMirrorHost(spec.RegistryMirror.Upstream), spec.RegistryMirror.Endpoint)}
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to marshal kind config: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "kind-config-*.yaml")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to create temporary kind config: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", noop, fmt.Errorf("failed to write temporary kind config: %w", err)
+	}
+	f.Close()
+
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
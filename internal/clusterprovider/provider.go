@@ -0,0 +1,83 @@
+// Package clusterprovider abstracts cluster provisioning behind a common
+// ClusterProvider interface, so the mage layer can drive CI runners,
+// laptops, and prod-like environments through the same targets regardless
+// of whether clusters are backed by kind, k3d, or an existing BYO
+// kubeconfig.
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// PortMapping exposes a container port on the host, e.g. for ingress.
+type PortMapping struct {
+	ContainerPort int
+	HostPort      int
+	Protocol      string // "TCP" or "UDP"; defaults to "TCP"
+}
+
+// RegistryMirror points image pulls at an internal mirror, so contributors
+// on restricted networks aren't blocked on reaching public registries.
+// Providers key their mirror configuration on Upstream's bare hostname
+// (see registryMirrorHost); only Endpoint carries a scheme.
+type RegistryMirror struct {
+	Upstream string // e.g. "https://registry-1.docker.io"
+	Endpoint string // e.g. "https://mirror.internal.example.com"
+}
+
+// ClusterSpec describes the cluster a ClusterProvider should create.
+type ClusterSpec struct {
+	Name           string
+	Nodes          int // total node count, including the control plane; 0 or 1 means single-node
+	PortMappings   []PortMapping
+	RegistryMirror *RegistryMirror
+}
+
+// ClusterInfo summarizes a running cluster.
+type ClusterInfo struct {
+	Name    string
+	Nodes   int
+	Version string
+}
+
+// ClusterProvider provisions and inspects clusters for a specific backend.
+type ClusterProvider interface {
+	Exists(name string) (bool, error)
+	Create(ctx context.Context, spec ClusterSpec) error
+	Delete(name string) error
+	Kubeconfig(name string) ([]byte, error)
+	Info(name string) (ClusterInfo, error)
+	// SelectContext points the ambient kubeconfig's current-context at name,
+	// so bare kubectl/helm invocations that don't thread a context or
+	// kubeconfig through explicitly still target the right cluster.
+	SelectContext(name string) error
+}
+
+// registryMirrorHost returns the bare hostname a RegistryMirror.Upstream
+// URL documents (e.g. "registry-1.docker.io" for
+// "https://registry-1.docker.io"), since both kind and k3d key their mirror
+// configuration on the hostname and only use a scheme in the endpoint. A
+// value with no scheme is assumed to already be a bare hostname.
+func registryMirrorHost(upstream string) string {
+	if u, err := url.Parse(upstream); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return upstream
+}
+
+// New resolves a ClusterProvider by name. An empty name selects the kind
+// provider, preserving the mage layer's original default.
+func New(provider string) (ClusterProvider, error) {
+	switch provider {
+	case "", "kind":
+		return &KindProvider{}, nil
+	case "k3d":
+		return &K3DProvider{}, nil
+	case "existing":
+		return &ExistingProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cluster provider '%s' (want kind, k3d, or existing)", provider)
+	}
+}
@@ -0,0 +1,20 @@
+package clusterprovider
+
+import "testing"
+
+func TestRegistryMirrorHost(t *testing.T) {
+	tests := []struct {
+		upstream string
+		want     string
+	}{
+		{upstream: "https://registry-1.docker.io", want: "registry-1.docker.io"},
+		{upstream: "http://quay.io", want: "quay.io"},
+		{upstream: "registry-1.docker.io", want: "registry-1.docker.io"},
+	}
+
+	for _, tt := range tests {
+		if got := registryMirrorHost(tt.upstream); got != tt.want {
+			t.Errorf("registryMirrorHost(%q) = %q, want %q", tt.upstream, got, tt.want)
+		}
+	}
+}
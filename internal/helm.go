@@ -2,70 +2,207 @@ package internal
 
 import (
 	"fmt"
+	"os"
 	"time"
 
-	"github.com/magefile/mage/sh"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
 )
 
+// HelmClient wraps the Helm Go SDK so mage targets can depend on an
+// interface instead of shelling out to the helm binary. This makes the
+// release lifecycle typed and lets callers fake it out in unit tests.
+type HelmClient interface {
+	ReleaseExists(name, namespace string) (bool, error)
+	EnsureHelmRepo(name, url string) error
+	InstallHelmChart(name, chart, namespace, version string, values map[string]interface{}) error
+	UpgradeHelmChart(name, chart, namespace, version string, values map[string]interface{}) error
+	UninstallHelmChart(name, namespace string) error
+	GetHelmChartStatus(name, namespace string) (*release.Release, error)
+}
+
+// helmSDKClient is the default HelmClient, backed directly by
+// helm.sh/helm/v3 rather than a forked helm binary.
+type helmSDKClient struct {
+	settings *cli.EnvSettings
+}
+
+// NewHelmClient builds a HelmClient from the ambient Helm environment
+// (KUBECONFIG, HELM_* env vars, repository config, etc.).
+func NewHelmClient() HelmClient {
+	return &helmSDKClient{settings: cli.New()}
+}
+
+// configuration initializes an action.Configuration scoped to namespace.
+func (h *helmSDKClient) configuration(namespace string) (*action.Configuration, error) {
+	cfg := new(action.Configuration)
+	if err := cfg.Init(h.settings.RESTClientGetter(), namespace, "secret", func(format string, v ...interface{}) {
+		fmt.Printf(format+"\n", v...)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm action configuration: %w", err)
+	}
+
+	registryClient, err := registry.NewClient(
+		registry.ClientOptDebug(h.settings.Debug),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptCredentialsFile(h.settings.RegistryConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize helm registry client: %w", err)
+	}
+	cfg.RegistryClient = registryClient
+
+	return cfg, nil
+}
+
 // ReleaseExists checks if a helm release exists in the specified namespace
-func ReleaseExists(name, namespace string) (bool, error) {
-	err := sh.Run("helm", "status", name, "--namespace", namespace)
+func (h *helmSDKClient) ReleaseExists(name, namespace string) (bool, error) {
+	cfg, err := h.configuration(namespace)
 	if err != nil {
-		// If helm status fails, the release doesn't exist
-		return false, nil
+		return false, err
 	}
-	return true, nil
+
+	list := action.NewList(cfg)
+	list.All = true
+	list.Filter = fmt.Sprintf("^%s$", name)
+
+	releases, err := list.Run()
+	if err != nil {
+		return false, fmt.Errorf("failed to list helm releases in namespace '%s': %w", namespace, err)
+	}
+
+	return len(releases) > 0, nil
 }
 
-// EnsureHelmRepo adds a helm repository if it doesn't already exist
-func EnsureHelmRepo(name, url string) error {
+// EnsureHelmRepo adds a helm repository, refreshing its index file even if
+// the repository is already registered so stale charts/versions don't
+// linger across repeated calls.
+func (h *helmSDKClient) EnsureHelmRepo(name, url string) error {
 	fmt.Printf("📦 Ensuring helm repository '%s' is available...\n", name)
-	return sh.Run("helm", "repo", "add", name, url)
-}
 
-// WaitForNamespaceDeleted waits for a namespace to be completely deleted
-func WaitForNamespaceDeleted(namespace string) error {
-	fmt.Printf("⏳ Waiting for namespace '%s' to be fully deleted...\n", namespace)
+	repoFile := h.settings.RepositoryConfig
 
-	for i := 0; i < 60; i++ { // Wait up to 60 seconds
-		err := sh.Run("kubectl", "get", "namespace", namespace)
-		if err != nil {
-			// If kubectl get namespace fails, the namespace is gone
-			fmt.Printf("✅ Namespace '%s' has been deleted\n", namespace)
-			return nil
+	var file repo.File
+	if b, err := os.ReadFile(repoFile); err == nil {
+		if err := yaml.Unmarshal(b, &file); err != nil {
+			return fmt.Errorf("failed to parse helm repository file '%s': %w", repoFile, err)
 		}
-		time.Sleep(1 * time.Second)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read helm repository file '%s': %w", repoFile, err)
+	}
+
+	entry := repo.Entry{Name: name, URL: url}
+	chartRepo, err := repo.NewChartRepository(&entry, getter.All(h.settings))
+	if err != nil {
+		return fmt.Errorf("failed to create helm chart repository '%s': %w", name, err)
+	}
+	if _, err := chartRepo.DownloadIndexFile(); err != nil {
+		return fmt.Errorf("failed to fetch index for helm repository '%s': %w", name, err)
+	}
+
+	file.Update(&entry)
+	if err := file.WriteFile(repoFile, 0644); err != nil {
+		return fmt.Errorf("failed to write helm repository file '%s': %w", repoFile, err)
 	}
 
-	return fmt.Errorf("timeout waiting for namespace '%s' to be deleted", namespace)
+	return nil
 }
 
 // InstallHelmChart installs a Helm chart
-func InstallHelmChart(name, chart, namespace, version string, values ...string) error {
-	args := []string{"install", name, chart, "--namespace", namespace}
-	if version != "" {
-		args = append(args, "--version", version)
+func (h *helmSDKClient) InstallHelmChart(name, chart, namespace, version string, values map[string]interface{}) error {
+	cfg, err := h.configuration(namespace)
+	if err != nil {
+		return err
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.CreateNamespace = true
+	install.Version = version
+	install.Wait = true
+	install.Timeout = 5 * time.Minute
+
+	chartPath, err := install.ChartPathOptions.LocateChart(chart, h.settings)
+	if err != nil {
+		return fmt.Errorf("failed to locate chart '%s': %w", chart, err)
+	}
+
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return fmt.Errorf("failed to load chart '%s': %w", chart, err)
 	}
-	args = append(args, values...)
-	return sh.Run("helm", args...)
+
+	if _, err := install.Run(chrt, values); err != nil {
+		return fmt.Errorf("failed to install release '%s': %w", name, err)
+	}
+
+	return nil
 }
 
 // UpgradeHelmChart upgrades a Helm chart
-func UpgradeHelmChart(name, chart, namespace, version string, values ...string) error {
-	args := []string{"upgrade", name, chart, "--namespace", namespace}
-	if version != "" {
-		args = append(args, "--version", version)
+func (h *helmSDKClient) UpgradeHelmChart(name, chart, namespace, version string, values map[string]interface{}) error {
+	cfg, err := h.configuration(namespace)
+	if err != nil {
+		return err
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.Version = version
+	upgrade.Wait = true
+	upgrade.Timeout = 5 * time.Minute
+
+	chartPath, err := upgrade.ChartPathOptions.LocateChart(chart, h.settings)
+	if err != nil {
+		return fmt.Errorf("failed to locate chart '%s': %w", chart, err)
+	}
+
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return fmt.Errorf("failed to load chart '%s': %w", chart, err)
 	}
-	args = append(args, values...)
-	return sh.Run("helm", args...)
+
+	if _, err := upgrade.Run(name, chrt, values); err != nil {
+		return fmt.Errorf("failed to upgrade release '%s': %w", name, err)
+	}
+
+	return nil
 }
 
 // UninstallHelmChart uninstalls a Helm chart
-func UninstallHelmChart(name, namespace string) error {
-	return sh.Run("helm", "uninstall", name, "--namespace", namespace)
+func (h *helmSDKClient) UninstallHelmChart(name, namespace string) error {
+	cfg, err := h.configuration(namespace)
+	if err != nil {
+		return err
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	if _, err := uninstall.Run(name); err != nil {
+		return fmt.Errorf("failed to uninstall release '%s': %w", name, err)
+	}
+
+	return nil
 }
 
 // GetHelmChartStatus gets the status of a Helm chart
-func GetHelmChartStatus(name, namespace string) error {
-	return sh.Run("helm", "status", name, "--namespace", namespace)
+func (h *helmSDKClient) GetHelmChartStatus(name, namespace string) (*release.Release, error) {
+	cfg, err := h.configuration(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	status := action.NewStatus(cfg)
+	rel, err := status.Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status for release '%s': %w", name, err)
+	}
+
+	return rel, nil
 }
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// TestRepoFileRoundTripPreservesTLSFields guards against regressing to a
+// yaml.v2 decoder: repo.File/repo.Entry only carry json tags, so a
+// lower-cased-field-name decoder silently zeroes out entries like
+// CertFile/KeyFile/CAFile on every read-modify-write of repositories.yaml.
+func TestRepoFileRoundTripPreservesTLSFields(t *testing.T) {
+	file := repo.File{
+		APIVersion: "v1",
+		Repositories: []*repo.Entry{
+			{
+				Name:                  "argo",
+				URL:                   "https://argoproj.github.io/argo-helm",
+				CertFile:              "/etc/certs/client.crt",
+				KeyFile:               "/etc/certs/client.key",
+				CAFile:                "/etc/certs/ca.crt",
+				InsecureSkipTLSverify: true,
+				PassCredentialsAll:    true,
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(&file)
+	if err != nil {
+		t.Fatalf("failed to marshal repo file: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "repositories.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write repo file: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read repo file: %v", err)
+	}
+
+	var got repo.File
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("failed to unmarshal repo file: %v", err)
+	}
+
+	if len(got.Repositories) != 1 {
+		t.Fatalf("expected 1 repository entry, got %d", len(got.Repositories))
+	}
+
+	entry := got.Repositories[0]
+	want := file.Repositories[0]
+	if entry.CertFile != want.CertFile {
+		t.Errorf("CertFile = %q, want %q", entry.CertFile, want.CertFile)
+	}
+	if entry.KeyFile != want.KeyFile {
+		t.Errorf("KeyFile = %q, want %q", entry.KeyFile, want.KeyFile)
+	}
+	if entry.CAFile != want.CAFile {
+		t.Errorf("CAFile = %q, want %q", entry.CAFile, want.CAFile)
+	}
+	if entry.InsecureSkipTLSverify != want.InsecureSkipTLSverify {
+		t.Errorf("InsecureSkipTLSverify = %v, want %v", entry.InsecureSkipTLSverify, want.InsecureSkipTLSverify)
+	}
+	if entry.PassCredentialsAll != want.PassCredentialsAll {
+		t.Errorf("PassCredentialsAll = %v, want %v", entry.PassCredentialsAll, want.PassCredentialsAll)
+	}
+}
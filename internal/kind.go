@@ -1,33 +1,46 @@
 package internal
 
 import (
-	"encoding/base64"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strconv"
-	"strings"
-	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
-	"github.com/adrg/xdg"
 	"github.com/magefile/mage/sh"
 )
 
-// ClusterExists checks if the specified kind cluster exists
+// kindContext returns the kubeconfig context name kind registers for the
+// cluster named name.
+func kindContext(name string) string {
+	return "kind-" + name
+}
+
+// ClusterExists reports whether the specified kind cluster is actually
+// reachable over its kubeconfig context (honoring $KUBECONFIG), rather than
+// merely checking that the context name is present: a stale context left
+// behind by a cluster torn down out-of-band, or one missing because it was
+// renamed, must not be mistaken for ground truth about the cluster itself.
 func ClusterExists(name string) (bool, error) {
-	clusters, err := sh.Output("kind", "get", "clusters")
+	config, err := restConfigForContext(kindContext(name))
 	if err != nil {
-		return false, fmt.Errorf("failed to get clusters: %w", err)
+		// No matching kubeconfig context: nothing to reach.
+		return false, nil
 	}
+	config.Timeout = 5 * time.Second
 
-	for _, cluster := range strings.Split(clusters, "\n") {
-		if strings.TrimSpace(cluster) == name {
-			return true, nil
-		}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to build kubernetes clientset: %w", err)
+	}
+
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return false, nil
 	}
 
-	return false, nil
+	return true, nil
 }
 
 // CreateCluster creates a new kind cluster with the given name
@@ -45,135 +58,26 @@ func ExportKubeconfig(name string) error {
 	return sh.Run("kind", "export", "kubeconfig", "--name", name)
 }
 
-// GetClusterInfo gets cluster info for the given cluster
-func GetClusterInfo(name string) (string, error) {
-	return sh.Output("kubectl", "cluster-info", "--context", "kind-"+name)
-}
-
-// GetNodeStatus runs kubectl get nodes for the given cluster
-func GetNodeStatus(name string) error {
-	return sh.Run("kubectl", "get", "nodes", "--context", "kind-"+name)
-}
-
-// PortForwardPIDFile returns the path to the PID file for port forwarding
-func PortForwardPIDFile(service, namespace string) string {
-	return filepath.Join(xdg.DataHome, "kargo", fmt.Sprintf("port-forward-%s-%s.pid", service, namespace))
-}
-
-// IsPortForwardRunning checks if a port forwarding process is already running
-func IsPortForwardRunning(service, namespace string) (bool, int, error) {
-	pidFile := PortForwardPIDFile(service, namespace)
-
-	// Check if PID file exists
-	if _, err := os.Stat(pidFile); os.IsNotExist(err) {
-		return false, 0, nil
-	}
-
-	// Read PID from file
-	pidBytes, err := os.ReadFile(pidFile)
-	if err != nil {
-		return false, 0, fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(pidBytes)))
-	if err != nil {
-		return false, 0, fmt.Errorf("failed to parse PID: %w", err)
-	}
-
-	// Check if process is still running
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false, 0, nil
-	}
-
-	// Send signal 0 to check if process exists
-	err = process.Signal(syscall.Signal(0))
-	if err != nil {
-		// Process doesn't exist, clean up PID file
-		os.Remove(pidFile)
-		return false, 0, nil
-	}
-
-	return true, pid, nil
-}
-
-// StartPortForward starts a port forwarding process in the background using Go standard library
-func StartPortForward(service, namespace string, localPort, remotePort int) (int, error) {
-	// Create kargo data directory if it doesn't exist
-	kargoDir := filepath.Join(xdg.DataHome, "kargo")
-	if err := os.MkdirAll(kargoDir, 0755); err != nil {
-		return 0, fmt.Errorf("failed to create kargo data directory: %w", err)
-	}
-
-	// Start kubectl port-forward directly
-	cmd := exec.Command("kubectl", "port-forward",
-		fmt.Sprintf("svc/%s", service),
-		fmt.Sprintf("%d:%d", localPort, remotePort),
-		"--namespace", namespace)
-
-	// Set process attributes to run in the background
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setsid: true, // Start in new session to detach from parent
-	}
-
-	// Redirect output to avoid blocking
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Start the process
-	if err := cmd.Start(); err != nil {
-		return 0, fmt.Errorf("failed to start port-forward: %w", err)
-	}
-
-	// Save PID to file
-	pidFile := PortForwardPIDFile(service, namespace)
-	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0644); err != nil {
-		// If we can't save the PID file, kill the process
-		cmd.Process.Kill()
-		return 0, fmt.Errorf("failed to save PID file: %w", err)
-	}
-
-	return cmd.Process.Pid, nil
-}
-
-// StopPortForward stops a running port forwarding process
-func StopPortForward(service, namespace string) error {
-	// Check if port forwarding is running
-	running, pid, err := IsPortForwardRunning(service, namespace)
+// GetNodeStatus prints the Ready condition and kubelet version of every
+// node reported by clientset. The caller supplies ctx and clientset
+// instead of GetNodeStatus building its own, so it's cancellation-aware
+// and unit-testable with a fake clientset.
+func GetNodeStatus(ctx context.Context, clientset kubernetes.Interface) error {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to check port forwarding status: %w", err)
-	}
-
-	if !running {
-		return fmt.Errorf("port forwarding is not running")
+		return fmt.Errorf("failed to list nodes: %w", err)
 	}
 
-	// Kill the process group (since we used Setsid)
-	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to stop port forwarding process: %w", err)
+	fmt.Printf("%-40s%-12s%-20s\n", "NAME", "STATUS", "VERSION")
+	for _, node := range nodes.Items {
+		status := "NotReady"
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				status = "Ready"
+			}
+		}
+		fmt.Printf("%-40s%-12s%-20s\n", node.Name, status, node.Status.NodeInfo.KubeletVersion)
 	}
 
-	// Clean up PID file
-	pidFile := PortForwardPIDFile(service, namespace)
-	os.Remove(pidFile)
-
 	return nil
 }
-
-// GetArgoCDAdminPassword retrieves the ArgoCD admin password
-func GetArgoCDAdminPassword(namespace string) (string, error) {
-	// Get the admin password from the secret
-	password, err := sh.Output("kubectl", "get", "secret", "argocd-initial-admin-secret",
-		"--namespace", namespace, "-o", "jsonpath={.data.password}")
-	if err != nil {
-		return "", fmt.Errorf("failed to get ArgoCD admin password: %w", err)
-	}
-
-	// Decode base64 using Go standard library
-	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(password))
-	if err != nil {
-		return "", fmt.Errorf("failed to decode password: %w", err)
-	}
-
-	return string(decoded), nil
-}
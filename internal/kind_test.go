@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNodeStatus(t *testing.T) {
+	readyNode := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready-node"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	notReadyNode := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-ready-node"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	clientset := fake.NewSimpleClientset(&readyNode, &notReadyNode)
+
+	if err := GetNodeStatus(context.Background(), clientset); err != nil {
+		t.Fatalf("GetNodeStatus returned error: %v", err)
+	}
+}
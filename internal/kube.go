@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restConfigForContext builds a *rest.Config for the given kubeconfig
+// context (an empty contextName uses the current context), honoring
+// $KUBECONFIG.
+func restConfigForContext(contextName string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if contextName != "" {
+		overrides.CurrentContext = contextName
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+
+	return config, nil
+}
+
+// NewKubeClient builds a Kubernetes clientset and controller-runtime
+// client for the given kubeconfig context (an empty contextName uses the
+// current context), honoring $KUBECONFIG. It blocks until the API server
+// responds and at least one node reports Ready, so callers never observe a
+// half-up cluster.
+func NewKubeClient(ctx context.Context, contextName string) (kubernetes.Interface, client.Client, error) {
+	config, err := restConfigForContext(contextName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build kubernetes clientset: %w", err)
+	}
+
+	ctrlClient, err := client.New(config, client.Options{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build controller-runtime client: %w", err)
+	}
+
+	if err := waitForClusterReady(ctx, clientset); err != nil {
+		return nil, nil, err
+	}
+
+	return clientset, ctrlClient, nil
+}
+
+// waitForClusterReady blocks until the API server responds and at least
+// one node reports Ready.
+func waitForClusterReady(ctx context.Context, clientset kubernetes.Interface) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	for {
+		nodes, err := clientset.CoreV1().Nodes().List(timeoutCtx, metav1.ListOptions{})
+		if err == nil {
+			for _, node := range nodes.Items {
+				for _, cond := range node.Status.Conditions {
+					if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+						return nil
+					}
+				}
+			}
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return fmt.Errorf("timeout waiting for cluster to become ready: %w", timeoutCtx.Err())
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
+// WaitForNamespaceDeleted waits for a namespace to be completely deleted
+func WaitForNamespaceDeleted(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	fmt.Printf("⏳ Waiting for namespace '%s' to be fully deleted...\n", namespace)
+
+	for i := 0; i < 60; i++ { // Wait up to 60 seconds
+		_, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Printf("✅ Namespace '%s' has been deleted\n", namespace)
+			return nil
+		}
+		time.Sleep(1 * time.Second)
+	}
+
+	return fmt.Errorf("timeout waiting for namespace '%s' to be deleted", namespace)
+}
@@ -0,0 +1,419 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/adrg/xdg"
+)
+
+// portForwardWorkerEnvVar, when set, tells a re-executed copy of this
+// binary to run a single port-forward session in the foreground instead
+// of dispatching mage targets. StartPortForward sets it on the detached
+// child it spawns, so the forward itself is driven by client-go's SPDY
+// implementation rather than a shelled-out kubectl.
+const portForwardWorkerEnvVar = "KARGO_PORTFORWARD_WORKER"
+
+func init() {
+	if os.Getenv(portForwardWorkerEnvVar) == "" {
+		return
+	}
+	runPortForwardWorker()
+}
+
+// PortForwarder owns a single client-go SPDY port-forward session. If its
+// backing pod disappears (e.g. a restart), it transparently resolves a
+// new backing pod and re-dials rather than dying.
+type PortForwarder struct {
+	config     *rest.Config
+	clientset  kubernetes.Interface
+	namespace  string
+	service    string
+	localPort  int
+	remotePort int
+
+	stopCh  chan struct{}
+	readyCh chan struct{}
+	errCh   chan error
+}
+
+// newPortForwarder resolves contextName's rest.Config and starts
+// forwarding localPort to remotePort on a pod backing service in
+// namespace, honoring $KUBECONFIG.
+func newPortForwarder(contextName, service, namespace string, localPort, remotePort int) (*PortForwarder, error) {
+	config, err := restConfigForContext(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes clientset: %w", err)
+	}
+
+	return NewPortForwarder(config, clientset, namespace, service, localPort, remotePort)
+}
+
+// NewPortForwarder starts forwarding localPort to remotePort on a pod
+// backing service in namespace, using an already-built rest.Config and
+// clientset. Unlike StartPortForward, it runs in-process for the lifetime
+// of the returned *PortForwarder rather than spawning a detached child, so
+// callers that only need a forward up for a single mage invocation (e.g.
+// ArgoCD:Bootstrap) can Stop() it when they're done instead of going
+// through the XDG state file.
+func NewPortForwarder(config *rest.Config, clientset kubernetes.Interface, namespace, service string, localPort, remotePort int) (*PortForwarder, error) {
+	pf := &PortForwarder{
+		config:     config,
+		clientset:  clientset,
+		namespace:  namespace,
+		service:    service,
+		localPort:  localPort,
+		remotePort: remotePort,
+		stopCh:     make(chan struct{}),
+		readyCh:    make(chan struct{}),
+		errCh:      make(chan error, 1),
+	}
+
+	go pf.run()
+
+	return pf, nil
+}
+
+// Ready returns a channel that closes once the first forward connection
+// has been established.
+func (pf *PortForwarder) Ready() <-chan struct{} {
+	return pf.readyCh
+}
+
+// Stop tears down the port-forward session.
+func (pf *PortForwarder) Stop() {
+	close(pf.stopCh)
+}
+
+// Wait blocks until the forwarder exits, returning the error that ended
+// it (nil if it was ended via Stop).
+func (pf *PortForwarder) Wait() error {
+	return <-pf.errCh
+}
+
+// run drives the forward loop, re-dialing a freshly resolved backing pod
+// whenever the current connection drops.
+func (pf *PortForwarder) run() {
+	var markReady sync.Once
+
+	for {
+		select {
+		case <-pf.stopCh:
+			pf.errCh <- nil
+			return
+		default:
+		}
+
+		podName, err := resolveBackingPod(pf.stopCh, pf.clientset, pf.namespace, pf.service)
+		if err != nil {
+			pf.errCh <- err
+			return
+		}
+
+		sessionReady := make(chan struct{})
+		go func() {
+			select {
+			case <-sessionReady:
+				markReady.Do(func() { close(pf.readyCh) })
+			case <-pf.stopCh:
+			}
+		}()
+
+		err = pf.forwardToPod(podName, sessionReady)
+
+		select {
+		case <-pf.stopCh:
+			pf.errCh <- nil
+			return
+		default:
+		}
+
+		if err != nil {
+			// The backing pod most likely restarted or was rescheduled;
+			// back off briefly and resolve a new one.
+			time.Sleep(2 * time.Second)
+			continue
+		}
+	}
+}
+
+// forwardToPod opens a single SPDY port-forward stream to podName and
+// blocks until it ends.
+func (pf *PortForwarder) forwardToPod(podName string, readyCh chan struct{}) error {
+	req := pf.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pf.namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
+	if err != nil {
+		return fmt.Errorf("failed to build SPDY round tripper: %w", err)
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	fw, err := portforward.New(dialer,
+		[]string{fmt.Sprintf("%d:%d", pf.localPort, pf.remotePort)},
+		pf.stopCh, readyCh, io.Discard, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to set up port-forward to pod '%s': %w", podName, err)
+	}
+
+	return fw.ForwardPorts()
+}
+
+// resolveBackingPod resolves service's selector and returns the name of a
+// currently running pod it backs, using an informer cache so repeated
+// calls (after a pod restart) pick up the replacement pod.
+func resolveBackingPod(stopCh <-chan struct{}, clientset kubernetes.Interface, namespace, service string) (string, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(context.Background(), service, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get service '%s/%s': %w", namespace, service, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", fmt.Errorf("service '%s/%s' has no selector to resolve a backing pod", namespace, service)
+	}
+	selector := labels.SelectorFromSet(svc.Spec.Selector).String()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector
+		}))
+	podInformer := factory.Core().V1().Pods().Informer()
+
+	factory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, podInformer.HasSynced) {
+		return "", fmt.Errorf("failed to sync pod cache for service '%s/%s'", namespace, service)
+	}
+
+	for _, obj := range podInformer.GetStore().List() {
+		if pod, ok := obj.(*corev1.Pod); ok && pod.Status.Phase == corev1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no running pod currently backs service '%s/%s'", namespace, service)
+}
+
+// portForwardState is the JSON record persisted alongside a running
+// port-forward so IsPortForwardRunning/StopPortForward can find and
+// verify it from a separate mage invocation.
+type portForwardState struct {
+	Service     string    `json:"service"`
+	Namespace   string    `json:"namespace"`
+	LocalPort   int       `json:"localPort"`
+	RemotePort  int       `json:"remotePort"`
+	ContextHash string    `json:"contextHash"`
+	PID         int       `json:"pid"`
+	StartedAt   time.Time `json:"startedAt"`
+}
+
+// contextHash returns a short fingerprint of a cluster's identity (its API
+// server host plus CA bundle) rather than just its kubeconfig context name,
+// so recreating a cluster under the same context name (e.g. `kind delete`
+// followed by `kind create`) changes the hash even though the name didn't.
+func contextHash(config *rest.Config) string {
+	sum := sha256.Sum256([]byte(config.Host + string(config.TLSClientConfig.CAData)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// PortForwardStateFile returns the path to the state file for a
+// service/namespace port-forward.
+func PortForwardStateFile(service, namespace string) string {
+	return filepath.Join(xdg.DataHome, "kargo", fmt.Sprintf("port-forward-%s-%s.json", service, namespace))
+}
+
+// IsPortForwardRunning checks if a port-forward process is already running
+// for service/namespace and was started against the cluster currently
+// reachable under contextName. A state file whose PID is dead, or whose
+// recorded ContextHash no longer matches the cluster behind contextName
+// (e.g. the cluster was deleted and recreated under the same context
+// name), is treated as stale and removed.
+func IsPortForwardRunning(service, namespace, contextName string) (bool, int, error) {
+	stateFile := PortForwardStateFile(service, namespace)
+
+	data, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return false, 0, nil
+	} else if err != nil {
+		return false, 0, fmt.Errorf("failed to read port-forward state file: %w", err)
+	}
+
+	var state portForwardState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, 0, fmt.Errorf("failed to parse port-forward state file: %w", err)
+	}
+
+	process, err := os.FindProcess(state.PID)
+	if err != nil {
+		os.Remove(stateFile)
+		return false, 0, nil
+	}
+
+	// On Windows, os.FindProcess already fails for a PID with no live
+	// process, and Process.Signal supports only os.Kill.
+	if runtime.GOOS != "windows" {
+		if err := process.Signal(syscall.Signal(0)); err != nil {
+			os.Remove(stateFile)
+			return false, 0, nil
+		}
+	}
+
+	if config, err := restConfigForContext(contextName); err == nil {
+		if contextHash(config) != state.ContextHash {
+			terminateProcessGroup(state.PID)
+			os.Remove(stateFile)
+			return false, 0, nil
+		}
+	}
+
+	return true, state.PID, nil
+}
+
+// StartPortForward starts a port-forward to service/remotePort in
+// namespace on localPort, using contextName's kubeconfig context. The
+// forward itself is driven by client-go's SPDY implementation in a
+// detached child process (so it survives this mage invocation exiting),
+// rather than shelling out to kubectl.
+func StartPortForward(contextName, service, namespace string, localPort, remotePort int) (int, error) {
+	if running, pid, _ := IsPortForwardRunning(service, namespace, contextName); running {
+		return pid, fmt.Errorf("port-forward for service '%s' in namespace '%s' is already running (pid %d)", service, namespace, pid)
+	}
+
+	config, err := restConfigForContext(contextName)
+	if err != nil {
+		return 0, err
+	}
+
+	kargoDir := filepath.Join(xdg.DataHome, "kargo")
+	if err := os.MkdirAll(kargoDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create kargo data directory: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve current executable: %w", err)
+	}
+
+	cmd := exec.Command(exe)
+	cmd.Env = append(os.Environ(),
+		portForwardWorkerEnvVar+"=1",
+		"KARGO_PF_CONTEXT="+contextName,
+		"KARGO_PF_SERVICE="+service,
+		"KARGO_PF_NAMESPACE="+namespace,
+		fmt.Sprintf("KARGO_PF_LOCAL_PORT=%d", localPort),
+		fmt.Sprintf("KARGO_PF_REMOTE_PORT=%d", remotePort),
+	)
+	detachFromParent(cmd)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start port-forward: %w", err)
+	}
+
+	state := portForwardState{
+		Service:     service,
+		Namespace:   namespace,
+		LocalPort:   localPort,
+		RemotePort:  remotePort,
+		ContextHash: contextHash(config),
+		PID:         cmd.Process.Pid,
+		StartedAt:   time.Now(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		cmd.Process.Kill()
+		return 0, fmt.Errorf("failed to marshal port-forward state: %w", err)
+	}
+
+	if err := os.WriteFile(PortForwardStateFile(service, namespace), data, 0644); err != nil {
+		cmd.Process.Kill()
+		return 0, fmt.Errorf("failed to save port-forward state file: %w", err)
+	}
+
+	return cmd.Process.Pid, nil
+}
+
+// StopPortForward stops a running port-forward process started against
+// the cluster currently reachable under contextName.
+func StopPortForward(service, namespace, contextName string) error {
+	running, pid, err := IsPortForwardRunning(service, namespace, contextName)
+	if err != nil {
+		return fmt.Errorf("failed to check port-forward status: %w", err)
+	}
+
+	if !running {
+		return fmt.Errorf("port forwarding is not running")
+	}
+
+	if err := terminateProcessGroup(pid); err != nil {
+		return fmt.Errorf("failed to stop port-forward process: %w", err)
+	}
+
+	os.Remove(PortForwardStateFile(service, namespace))
+
+	return nil
+}
+
+// runPortForwardWorker is the entry point for the detached child process
+// StartPortForward spawns: it runs a single PortForwarder in the
+// foreground until it receives SIGTERM.
+func runPortForwardWorker() {
+	contextName := os.Getenv("KARGO_PF_CONTEXT")
+	service := os.Getenv("KARGO_PF_SERVICE")
+	namespace := os.Getenv("KARGO_PF_NAMESPACE")
+	localPort, _ := strconv.Atoi(os.Getenv("KARGO_PF_LOCAL_PORT"))
+	remotePort, _ := strconv.Atoi(os.Getenv("KARGO_PF_REMOTE_PORT"))
+
+	pf, err := newPortForwarder(contextName, service, namespace, localPort, remotePort)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "port-forward worker failed to start: %v\n", err)
+		os.Exit(1)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigCh
+		pf.Stop()
+	}()
+
+	if err := pf.Wait(); err != nil {
+		fmt.Fprintf(os.Stderr, "port-forward worker exited: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
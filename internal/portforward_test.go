@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestContextHashDiffersOnClusterRecreation(t *testing.T) {
+	original := &rest.Config{Host: "https://127.0.0.1:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca-v1")}}
+	sameAgain := &rest.Config{Host: "https://127.0.0.1:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca-v1")}}
+	recreated := &rest.Config{Host: "https://127.0.0.1:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca-v2")}}
+
+	if contextHash(original) != contextHash(sameAgain) {
+		t.Errorf("expected identical configs to hash the same")
+	}
+	if contextHash(original) == contextHash(recreated) {
+		t.Errorf("expected a cluster recreated under the same context name (new CA) to hash differently")
+	}
+}
+
+func TestPortForwardStateRoundTrip(t *testing.T) {
+	want := portForwardState{
+		Service:     "argocd-server",
+		Namespace:   "argocd",
+		LocalPort:   8080,
+		RemotePort:  443,
+		ContextHash: "deadbeefcafe",
+		PID:         1234,
+		StartedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(&want)
+	if err != nil {
+		t.Fatalf("failed to marshal port-forward state: %v", err)
+	}
+
+	var got portForwardState
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal port-forward state: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("round-tripped state = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveBackingPodReResolvesAfterRestart(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-server", Namespace: "argocd"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "argocd-server"}},
+	}
+	original := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-server-aaaa", Namespace: "argocd", Labels: map[string]string{"app": "argocd-server"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	clientset := fake.NewSimpleClientset(svc, original)
+
+	got, err := resolveBackingPod(stopCh, clientset, "argocd", "argocd-server")
+	if err != nil {
+		t.Fatalf("resolveBackingPod returned error: %v", err)
+	}
+	if got != original.Name {
+		t.Fatalf("resolveBackingPod = %q, want %q", got, original.Name)
+	}
+
+	// The pod restarts under a new name; a fresh resolution should pick up
+	// the replacement rather than the one that no longer exists.
+	if err := clientset.CoreV1().Pods("argocd").Delete(context.Background(), original.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete original pod: %v", err)
+	}
+	replacement := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-server-bbbb", Namespace: "argocd", Labels: map[string]string{"app": "argocd-server"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if _, err := clientset.CoreV1().Pods("argocd").Create(context.Background(), replacement, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create replacement pod: %v", err)
+	}
+
+	got, err = resolveBackingPod(stopCh, clientset, "argocd", "argocd-server")
+	if err != nil {
+		t.Fatalf("resolveBackingPod returned error after restart: %v", err)
+	}
+	if got != replacement.Name {
+		t.Fatalf("resolveBackingPod after restart = %q, want %q", got, replacement.Name)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package internal
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachFromParent puts cmd in its own session, so it survives the
+// parent mage invocation exiting and StopPortForward can signal the
+// whole process group rather than just the immediate child.
+func detachFromParent(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// terminateProcessGroup sends SIGTERM to the process group started by
+// detachFromParent.
+func terminateProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// detachFromParent starts cmd in its own process group via
+// CREATE_NEW_PROCESS_GROUP, the Windows equivalent of the Setsid-based
+// detachment used on Unix (Windows' SysProcAttr has no Setsid field).
+func detachFromParent(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminateProcessGroup kills the process group started by
+// detachFromParent. Windows has no syscall.Kill(-pid, ...) equivalent, so
+// this shells out to taskkill's /T (tree) flag instead.
+func terminateProcessGroup(pid int) error {
+	if err := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(pid)).Run(); err != nil {
+		return fmt.Errorf("taskkill failed: %w", err)
+	}
+	return nil
+}
@@ -3,17 +3,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
 	"github.com/konflux-ci/kargo/internal"
+	"github.com/konflux-ci/kargo/internal/clusterprovider"
 	"github.com/magefile/mage/mg"
 	"github.com/magefile/mage/sh"
 )
 
-// Kind manages kind cluster operations
-type Kind mg.Namespace
+// Cluster manages cluster provisioning through the configured
+// ClusterProvider (kind by default; see KARGO_CLUSTER_PROVIDER / .kargo.yaml).
+type Cluster mg.Namespace
 
 // CertManager manages cert-manager operations
 type CertManager mg.Namespace
@@ -21,14 +27,31 @@ type CertManager mg.Namespace
 // ArgoCD manages ArgoCD operations
 type ArgoCD mg.Namespace
 
+// ClusterAPI manages turning the kind cluster into a Cluster API
+// management cluster and reconciling workload clusters on top of it.
+type ClusterAPI mg.Namespace
+
+// PortForward manages a standalone port-forward to the argocd-server
+// Service, independent of the in-process one ArgoCD:Bootstrap starts for
+// its own duration. Unlike that one, Start survives this mage invocation
+// exiting (a detached child process tracked via the XDG state file), so
+// `mage portforward:start` is useful on its own to reach the ArgoCD UI/API.
+type PortForward mg.Namespace
+
 const (
-	clusterName        = "kargo"
-	certManagerVersion = "v1.18.2"
-	certManagerNS      = "cert-manager"
-	argocdVersion      = "7.4.0"
-	argocdNS           = "argocd"
-	argocdRepoName     = "argo"
-	argocdRepoURL      = "https://argoproj.github.io/argo-helm"
+	clusterName         = "kargo"
+	certManagerVersion  = "v1.18.2"
+	certManagerNS       = "cert-manager"
+	argocdVersion       = "7.4.0"
+	argocdNS            = "argocd"
+	argocdRepoName      = "argo"
+	argocdRepoURL       = "https://argoproj.github.io/argo-helm"
+	clusterProvidersDir = "clusters/providers"
+	workloadClustersDir = "clusters/workloads"
+
+	argocdServerService   = "argocd-server"
+	argocdServerLocalPort = 8080
+	argocdServerAPIPort   = 443
 )
 
 // Default target - shows available targets
@@ -36,12 +59,51 @@ func Default() error {
 	return sh.Run("mage", "-l")
 }
 
-// Kind:Up creates or connects to a kind cluster named 'kargo'
-func (Kind) Up() error {
-	fmt.Println("🚀 Setting up kind cluster...")
+// clusterProvider resolves the configured ClusterProvider, so every
+// Cluster target drives the same provider selection logic.
+func clusterProvider() (clusterprovider.ClusterProvider, error) {
+	cfg, err := clusterprovider.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster provider config: %w", err)
+	}
+
+	provider, err := clusterprovider.New(cfg.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cluster provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+// writeTempKubeconfig writes data to a temporary file so bare kubectl
+// invocations can be scoped to it with --kubeconfig instead of trusting
+// the ambient current-context.
+func writeTempKubeconfig(data []byte) (string, func(), error) {
+	f, err := os.CreateTemp("", "kargo-kubeconfig-*.yaml")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary kubeconfig: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", nil, fmt.Errorf("failed to write temporary kubeconfig: %w", err)
+	}
+	f.Close()
+
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
+
+// Cluster:Up creates or connects to a cluster named 'kargo' using the
+// configured ClusterProvider
+func (Cluster) Up() error {
+	fmt.Println("🚀 Setting up cluster...")
+
+	provider, err := clusterProvider()
+	if err != nil {
+		return err
+	}
 
-	// Check if cluster already exists
-	exists, err := internal.ClusterExists(clusterName)
+	exists, err := provider.Exists(clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to check cluster existence: %w", err)
 	}
@@ -49,69 +111,67 @@ func (Kind) Up() error {
 	if exists {
 		fmt.Printf("✅ Cluster '%s' already exists\n", clusterName)
 	} else {
-		fmt.Printf("📦 Creating kind cluster '%s'...\n", clusterName)
-		err := internal.CreateCluster(clusterName)
-		if err != nil {
+		fmt.Printf("📦 Creating cluster '%s'...\n", clusterName)
+		if err := provider.Create(context.Background(), clusterprovider.ClusterSpec{Name: clusterName}); err != nil {
 			return fmt.Errorf("failed to create cluster: %w", err)
 		}
 		fmt.Printf("✅ Cluster '%s' created successfully\n", clusterName)
 	}
 
-	// Export kubeconfig
-	fmt.Printf("🔧 Exporting kubeconfig for cluster '%s'...\n", clusterName)
-	err = internal.ExportKubeconfig(clusterName)
-	if err != nil {
-		return fmt.Errorf("failed to export kubeconfig: %w", err)
+	if err := provider.SelectContext(clusterName); err != nil {
+		return fmt.Errorf("failed to select kubeconfig context for cluster '%s': %w", clusterName, err)
 	}
 
-	fmt.Printf("✅ Kind cluster '%s' is ready!\n", clusterName)
+	fmt.Printf("✅ Cluster '%s' is ready!\n", clusterName)
 	return nil
 }
 
-// Kind:UpClean forces recreation of the kind cluster (deletes existing cluster and creates new one)
-func (Kind) UpClean() error {
-	fmt.Println("🚀 Setting up kind cluster (clean recreation)...")
+// Cluster:UpClean forces recreation of the cluster (deletes existing cluster and creates new one)
+func (Cluster) UpClean() error {
+	fmt.Println("🚀 Setting up cluster (clean recreation)...")
+
+	provider, err := clusterProvider()
+	if err != nil {
+		return err
+	}
 
-	// Check if cluster already exists
-	exists, err := internal.ClusterExists(clusterName)
+	exists, err := provider.Exists(clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to check cluster existence: %w", err)
 	}
 
 	if exists {
 		fmt.Printf("🔄 Deleting existing cluster '%s'...\n", clusterName)
-		err := internal.DeleteCluster(clusterName)
-		if err != nil {
+		if err := provider.Delete(clusterName); err != nil {
 			return fmt.Errorf("failed to delete existing cluster: %w", err)
 		}
 		fmt.Printf("✅ Cluster '%s' deleted successfully\n", clusterName)
 	}
 
-	// Create new cluster
-	fmt.Printf("📦 Creating kind cluster '%s'...\n", clusterName)
-	err = internal.CreateCluster(clusterName)
-	if err != nil {
+	fmt.Printf("📦 Creating cluster '%s'...\n", clusterName)
+	if err := provider.Create(context.Background(), clusterprovider.ClusterSpec{Name: clusterName}); err != nil {
 		return fmt.Errorf("failed to create cluster: %w", err)
 	}
 	fmt.Printf("✅ Cluster '%s' created successfully\n", clusterName)
 
-	// Export kubeconfig
-	fmt.Printf("🔧 Exporting kubeconfig for cluster '%s'...\n", clusterName)
-	err = internal.ExportKubeconfig(clusterName)
-	if err != nil {
-		return fmt.Errorf("failed to export kubeconfig: %w", err)
+	if err := provider.SelectContext(clusterName); err != nil {
+		return fmt.Errorf("failed to select kubeconfig context for cluster '%s': %w", clusterName, err)
 	}
 
-	fmt.Printf("✅ Kind cluster '%s' is ready!\n", clusterName)
+	fmt.Printf("✅ Cluster '%s' is ready!\n", clusterName)
 	return nil
 }
 
-// Kind:Down tears down the kind cluster
-func (Kind) Down() error {
-	fmt.Println("🔥 Tearing down kind cluster...")
+// Cluster:Down tears down the cluster
+func (Cluster) Down() error {
+	fmt.Println("🔥 Tearing down cluster...")
 
-	// Check if cluster exists first
-	exists, err := internal.ClusterExists(clusterName)
+	provider, err := clusterProvider()
+	if err != nil {
+		return err
+	}
+
+	exists, err := provider.Exists(clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to check cluster existence: %w", err)
 	}
@@ -121,10 +181,8 @@ func (Kind) Down() error {
 		return nil
 	}
 
-	// Delete the cluster
-	fmt.Printf("🗑️  Deleting kind cluster '%s'...\n", clusterName)
-	err = internal.DeleteCluster(clusterName)
-	if err != nil {
+	fmt.Printf("🗑️  Deleting cluster '%s'...\n", clusterName)
+	if err := provider.Delete(clusterName); err != nil {
 		return fmt.Errorf("failed to delete cluster: %w", err)
 	}
 
@@ -132,12 +190,16 @@ func (Kind) Down() error {
 	return nil
 }
 
-// Kind:Status shows the status of the kind cluster
-func (Kind) Status() error {
-	fmt.Println("📊 Checking kind cluster status...")
+// Cluster:Status shows the status of the cluster
+func (Cluster) Status() error {
+	fmt.Println("📊 Checking cluster status...")
+
+	provider, err := clusterProvider()
+	if err != nil {
+		return err
+	}
 
-	// Check if cluster exists
-	exists, err := internal.ClusterExists(clusterName)
+	exists, err := provider.Exists(clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to check cluster existence: %w", err)
 	}
@@ -149,27 +211,34 @@ func (Kind) Status() error {
 
 	fmt.Printf("✅ Cluster '%s' exists\n", clusterName)
 
-	// Check kubeconfig
-	kubeconfigPath := os.Getenv("KUBECONFIG")
-	if kubeconfigPath == "" {
-		kubeconfigPath = os.Getenv("HOME") + "/.kube/config"
-	}
-
-	// Try to get cluster info
 	fmt.Printf("🔍 Checking cluster connectivity...\n")
-	output, err := internal.GetClusterInfo(clusterName)
+	info, err := provider.Info(clusterName)
 	if err != nil {
 		fmt.Printf("⚠️  Could not connect to cluster: %v\n", err)
-		fmt.Printf("💡 Try running 'mage kind:up' to ensure kubeconfig is exported\n")
+		fmt.Printf("💡 Try running 'mage cluster:up' to ensure the cluster is reachable\n")
 		return nil
 	}
 
-	fmt.Printf("✅ Cluster is accessible:\n%s\n", output)
+	fmt.Printf("✅ Cluster is accessible: version %s, %d node(s)\n", info.Version, info.Nodes)
 
-	// Get node status
-	fmt.Printf("🖥️  Node status:\n")
-	err = internal.GetNodeStatus(clusterName)
+	kubeconfig, err := provider.Kubeconfig(clusterName)
+	if err != nil {
+		fmt.Printf("⚠️  Could not get kubeconfig for node status: %v\n", err)
+		return nil
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
+		fmt.Printf("⚠️  Could not build rest config for node status: %v\n", err)
+		return nil
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Printf("⚠️  Could not build kubernetes client for node status: %v\n", err)
+		return nil
+	}
+
+	fmt.Println("🔍 Checking node status...")
+	if err := internal.GetNodeStatus(context.Background(), clientset); err != nil {
 		fmt.Printf("⚠️  Could not get node status: %v\n", err)
 	}
 
@@ -178,7 +247,14 @@ func (Kind) Status() error {
 
 // CertManager:Up installs or upgrades cert-manager using Helm
 func (CertManager) Up() error {
-	mg.Deps(Kind.Up)
+	mg.Deps(Cluster.Up)
+	return installOrUpgradeCertManager(internal.NewHelmClient())
+}
+
+// installOrUpgradeCertManager takes helmClient as a parameter rather than
+// constructing one itself, so CertManager:Up and CertManager:UpClean share a
+// single implementation.
+func installOrUpgradeCertManager(helmClient internal.HelmClient) error {
 	fmt.Println("🔐 Setting up cert-manager...")
 
 	// Create namespace if it doesn't exist
@@ -188,22 +264,24 @@ func (CertManager) Up() error {
 		fmt.Printf("ℹ️  Namespace '%s' might already exist\n", certManagerNS)
 	}
 
+	crdsEnabled := map[string]interface{}{"crds": map[string]interface{}{"enabled": true}}
+
 	// Check if cert-manager is already installed
-	exists, err := internal.ReleaseExists("cert-manager", certManagerNS)
+	exists, err := helmClient.ReleaseExists("cert-manager", certManagerNS)
 	if err != nil {
 		return fmt.Errorf("failed to check cert-manager installation: %w", err)
 	}
 
 	if exists {
 		fmt.Printf("🔄 cert-manager is already installed, upgrading to v%s...\n", certManagerVersion)
-		err = internal.UpgradeHelmChart("cert-manager", "oci://quay.io/jetstack/charts/cert-manager", certManagerNS, certManagerVersion, "--set", "crds.enabled=true")
+		err = helmClient.UpgradeHelmChart("cert-manager", "oci://quay.io/jetstack/charts/cert-manager", certManagerNS, certManagerVersion, crdsEnabled)
 		if err != nil {
 			return fmt.Errorf("failed to upgrade cert-manager: %w", err)
 		}
 		fmt.Printf("✅ cert-manager upgraded to v%s and is ready\n", certManagerVersion)
 	} else {
 		fmt.Printf("📦 Installing cert-manager v%s...\n", certManagerVersion)
-		err = internal.InstallHelmChart("cert-manager", "oci://quay.io/jetstack/charts/cert-manager", certManagerNS, certManagerVersion, "--set", "crds.enabled=true")
+		err = helmClient.InstallHelmChart("cert-manager", "oci://quay.io/jetstack/charts/cert-manager", certManagerNS, certManagerVersion, crdsEnabled)
 		if err != nil {
 			return fmt.Errorf("failed to install cert-manager: %w", err)
 		}
@@ -215,10 +293,17 @@ func (CertManager) Up() error {
 
 // CertManager:Down removes cert-manager and cleans up resources
 func (CertManager) Down() error {
+	return uninstallCertManager(internal.NewHelmClient())
+}
+
+// uninstallCertManager takes helmClient as a parameter rather than
+// constructing one itself, so CertManager:Down and CertManager:UpClean share
+// a single implementation.
+func uninstallCertManager(helmClient internal.HelmClient) error {
 	fmt.Println("🔥 Tearing down cert-manager...")
 
 	// Check if cert-manager is installed
-	exists, err := internal.ReleaseExists("cert-manager", certManagerNS)
+	exists, err := helmClient.ReleaseExists("cert-manager", certManagerNS)
 	if err != nil {
 		return fmt.Errorf("failed to check cert-manager installation: %w", err)
 	}
@@ -229,7 +314,7 @@ func (CertManager) Down() error {
 	}
 
 	// Uninstall the helm release
-	err = internal.UninstallHelmChart("cert-manager", certManagerNS)
+	err = helmClient.UninstallHelmChart("cert-manager", certManagerNS)
 	if err != nil {
 		return fmt.Errorf("failed to uninstall cert-manager: %w", err)
 	}
@@ -277,10 +362,16 @@ func (CertManager) UpClean() error {
 
 // CertManager:Status shows the status of cert-manager installation
 func (CertManager) Status() error {
+	return certManagerStatus(internal.NewHelmClient())
+}
+
+// certManagerStatus takes helmClient as a parameter rather than constructing
+// one itself, matching the other CertManager target functions.
+func certManagerStatus(helmClient internal.HelmClient) error {
 	fmt.Println("📊 Checking cert-manager status...")
 
 	// Check if helm release exists
-	exists, err := internal.ReleaseExists("cert-manager", certManagerNS)
+	exists, err := helmClient.ReleaseExists("cert-manager", certManagerNS)
 	if err != nil {
 		return fmt.Errorf("failed to check cert-manager release: %w", err)
 	}
@@ -294,9 +385,11 @@ func (CertManager) Status() error {
 
 	// Get helm status
 	fmt.Printf("🔍 Helm release status:\n")
-	err = internal.GetHelmChartStatus("cert-manager", certManagerNS)
+	rel, err := helmClient.GetHelmChartStatus("cert-manager", certManagerNS)
 	if err != nil {
 		fmt.Printf("⚠️  Could not get helm status: %v\n", err)
+	} else {
+		fmt.Printf("status: %s\n", rel.Info.Status)
 	}
 
 	// Check pod status
@@ -322,21 +415,22 @@ func (CertManager) Status() error {
 
 // ArgoCD:Up installs or upgrades ArgoCD using Helm
 func (ArgoCD) Up() error {
-	mg.Deps(Kind.Up)
+	mg.Deps(Cluster.Up)
+	return installOrUpgradeArgoCD(internal.NewHelmClient())
+}
+
+// installOrUpgradeArgoCD takes helmClient as a parameter rather than
+// constructing one itself, so ArgoCD:Up and ArgoCD:UpClean share a single
+// implementation.
+func installOrUpgradeArgoCD(helmClient internal.HelmClient) error {
 	fmt.Println("🚀 Setting up ArgoCD...")
 
 	// Ensure ArgoCD Helm repository is available
-	err := internal.EnsureHelmRepo(argocdRepoName, argocdRepoURL)
+	err := helmClient.EnsureHelmRepo(argocdRepoName, argocdRepoURL)
 	if err != nil {
 		return fmt.Errorf("failed to add ArgoCD Helm repository: %w", err)
 	}
 
-	// Update Helm repositories
-	err = sh.Run("helm", "repo", "update")
-	if err != nil {
-		return fmt.Errorf("failed to update Helm repositories: %w", err)
-	}
-
 	// Create namespace if it doesn't exist
 	err = sh.Run("kubectl", "create", "namespace", argocdNS)
 	if err != nil {
@@ -345,21 +439,21 @@ func (ArgoCD) Up() error {
 	}
 
 	// Check if ArgoCD is already installed
-	exists, err := internal.ReleaseExists("argo-cd", argocdNS)
+	exists, err := helmClient.ReleaseExists("argo-cd", argocdNS)
 	if err != nil {
 		return fmt.Errorf("failed to check ArgoCD installation: %w", err)
 	}
 
 	if exists {
 		fmt.Printf("🔄 ArgoCD is already installed, upgrading to v%s...\n", argocdVersion)
-		err = internal.UpgradeHelmChart("argo-cd", argocdRepoName+"/argo-cd", argocdNS, argocdVersion)
+		err = helmClient.UpgradeHelmChart("argo-cd", argocdRepoName+"/argo-cd", argocdNS, argocdVersion, nil)
 		if err != nil {
 			return fmt.Errorf("failed to upgrade ArgoCD: %w", err)
 		}
 		fmt.Printf("✅ ArgoCD upgraded to v%s and is ready\n", argocdVersion)
 	} else {
 		fmt.Printf("📦 Installing ArgoCD v%s...\n", argocdVersion)
-		err = internal.InstallHelmChart("argo-cd", argocdRepoName+"/argo-cd", argocdNS, argocdVersion)
+		err = helmClient.InstallHelmChart("argo-cd", argocdRepoName+"/argo-cd", argocdNS, argocdVersion, nil)
 		if err != nil {
 			return fmt.Errorf("failed to install ArgoCD: %w", err)
 		}
@@ -371,10 +465,17 @@ func (ArgoCD) Up() error {
 
 // ArgoCD:Down removes ArgoCD and cleans up resources
 func (ArgoCD) Down() error {
+	return uninstallArgoCD(internal.NewHelmClient())
+}
+
+// uninstallArgoCD takes helmClient as a parameter rather than constructing
+// one itself, so ArgoCD:Down and ArgoCD:UpClean share a single
+// implementation.
+func uninstallArgoCD(helmClient internal.HelmClient) error {
 	fmt.Println("🔥 Tearing down ArgoCD...")
 
 	// Check if ArgoCD is installed
-	exists, err := internal.ReleaseExists("argo-cd", argocdNS)
+	exists, err := helmClient.ReleaseExists("argo-cd", argocdNS)
 	if err != nil {
 		return fmt.Errorf("failed to check ArgoCD installation: %w", err)
 	}
@@ -385,7 +486,7 @@ func (ArgoCD) Down() error {
 	}
 
 	// Uninstall the helm release
-	err = internal.UninstallHelmChart("argo-cd", argocdNS)
+	err = helmClient.UninstallHelmChart("argo-cd", argocdNS)
 	if err != nil {
 		return fmt.Errorf("failed to uninstall ArgoCD: %w", err)
 	}
@@ -420,10 +521,16 @@ func (ArgoCD) UpClean() error {
 
 // ArgoCD:Status shows the status of ArgoCD installation
 func (ArgoCD) Status() error {
+	return argoCDStatus(internal.NewHelmClient())
+}
+
+// argoCDStatus takes helmClient as a parameter rather than constructing one
+// itself, matching the other ArgoCD target functions.
+func argoCDStatus(helmClient internal.HelmClient) error {
 	fmt.Println("📊 Checking ArgoCD status...")
 
 	// Check if helm release exists
-	exists, err := internal.ReleaseExists("argo-cd", argocdNS)
+	exists, err := helmClient.ReleaseExists("argo-cd", argocdNS)
 	if err != nil {
 		return fmt.Errorf("failed to check ArgoCD release: %w", err)
 	}
@@ -437,9 +544,11 @@ func (ArgoCD) Status() error {
 
 	// Get helm status
 	fmt.Printf("🔍 Helm release status:\n")
-	err = internal.GetHelmChartStatus("argo-cd", argocdNS)
+	rel, err := helmClient.GetHelmChartStatus("argo-cd", argocdNS)
 	if err != nil {
 		fmt.Printf("⚠️  Could not get helm status: %v\n", err)
+	} else {
+		fmt.Printf("status: %s\n", rel.Info.Status)
 	}
 
 	// Check pod status
@@ -462,3 +571,218 @@ func (ArgoCD) Status() error {
 
 	return nil
 }
+
+// ArgoCD:Bootstrap registers every Cluster API workload cluster listed by
+// ListWorkloadClusters (those in the management kubeconfig's current-context
+// namespace) as an ArgoCD destination, port-forwarding to the argocd-server Service on the
+// management cluster (resolved through the configured ClusterProvider, so
+// it works regardless of KARGO_CLUSTER_PROVIDER) to reach the API. ArgoCD
+// auto-registers the cluster it runs on, so the management cluster itself
+// isn't registered again here.
+func (ArgoCD) Bootstrap() error {
+	mg.Deps(ArgoCD.Up)
+
+	provider, err := clusterProvider()
+	if err != nil {
+		return err
+	}
+
+	managementKubeconfig, err := provider.Kubeconfig(clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to get kubeconfig for cluster '%s': %w", clusterName, err)
+	}
+
+	managementKubeconfigPath, cleanup, err := writeTempKubeconfig(managementKubeconfig)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	fmt.Println("🔍 Looking up Cluster API workload clusters...")
+	workloadClusters, err := internal.ListWorkloadClusters(managementKubeconfigPath)
+	if err != nil {
+		return err
+	}
+	if len(workloadClusters) == 0 {
+		fmt.Println("ℹ️  No Cluster API workload clusters found, nothing to register")
+		return nil
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(managementKubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to build rest config for cluster '%s': %w", clusterName, err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build kubernetes client for cluster '%s': %w", clusterName, err)
+	}
+
+	fmt.Println("🔌 Starting port-forward to argocd-server...")
+	pf, err := internal.NewPortForwarder(restConfig, clientset, argocdNS, argocdServerService, argocdServerLocalPort, argocdServerAPIPort)
+	if err != nil {
+		return fmt.Errorf("failed to start port-forward to argocd-server: %w", err)
+	}
+	defer pf.Stop()
+
+	select {
+	case <-pf.Ready():
+	case <-time.After(30 * time.Second):
+		return fmt.Errorf("timed out waiting for port-forward to argocd-server to become ready")
+	}
+
+	ctx := context.Background()
+	argocd := internal.NewArgoCDClient("localhost:8080", true)
+	session, err := argocd.Login(ctx, clientset, argocdNS)
+	if err != nil {
+		return fmt.Errorf("failed to log in to ArgoCD: %w", err)
+	}
+
+	for _, name := range workloadClusters {
+		fmt.Printf("🔗 Registering cluster '%s'...\n", name)
+		kubeconfigBytes, err := internal.WorkloadClusterKubeconfig(managementKubeconfigPath, name)
+		if err != nil {
+			return err
+		}
+		if err := session.RegisterCluster(ctx, kubeconfigBytes, name); err != nil {
+			return fmt.Errorf("failed to register cluster '%s': %w", name, err)
+		}
+	}
+
+	fmt.Println("✅ Workload clusters registered with ArgoCD")
+	return nil
+}
+
+// ClusterAPI:Up turns the kind cluster into a Cluster API management
+// cluster by installing the CAPI operator, the CAPD infra provider, the
+// kubeadm bootstrap/control-plane providers, and the Cluster API Add-on
+// Provider for Helm.
+func (ClusterAPI) Up() error {
+	mg.Deps(Cluster.Up)
+	fmt.Println("🚀 Bootstrapping Cluster API management cluster...")
+
+	if err := internal.ApplyCoreProvider(); err != nil {
+		return fmt.Errorf("failed to apply Cluster API core provider: %w", err)
+	}
+	if err := internal.ApplyBootstrapProvider(); err != nil {
+		return fmt.Errorf("failed to apply Cluster API bootstrap provider: %w", err)
+	}
+	if err := internal.ApplyInfraProvider(); err != nil {
+		return fmt.Errorf("failed to apply Cluster API infrastructure provider: %w", err)
+	}
+
+	fmt.Println("✅ Cluster API management cluster is ready!")
+	return nil
+}
+
+// ClusterAPI:Down removes the Cluster API provider namespaces, leaving the
+// underlying kind cluster intact.
+func (ClusterAPI) Down() error {
+	fmt.Println("🔥 Tearing down Cluster API providers...")
+
+	namespaces := []string{
+		internal.CAAPHSystemNamespace,
+		internal.CAPDSystemNamespace,
+		internal.CAPIKubeadmControlPlaneSystemNamespace,
+		internal.CAPIKubeadmBootstrapSystemNamespace,
+		internal.CAPISystemNamespace,
+		internal.CAPIOperatorNamespace,
+	}
+
+	for _, ns := range namespaces {
+		if err := sh.Run("kubectl", "delete", "namespace", ns, "--ignore-not-found"); err != nil {
+			return fmt.Errorf("failed to delete namespace '%s': %w", ns, err)
+		}
+	}
+
+	fmt.Println("✅ Cluster API providers torn down successfully")
+	return nil
+}
+
+// ClusterAPI:Status shows the status of every Cluster API provider
+// namespace.
+func (ClusterAPI) Status() error {
+	fmt.Println("📊 Checking Cluster API provider status...")
+
+	namespaces := []string{
+		internal.CAPIOperatorNamespace,
+		internal.CAPISystemNamespace,
+		internal.CAPIKubeadmBootstrapSystemNamespace,
+		internal.CAPIKubeadmControlPlaneSystemNamespace,
+		internal.CAPDSystemNamespace,
+		internal.CAAPHSystemNamespace,
+	}
+
+	for _, ns := range namespaces {
+		fmt.Printf("🔍 Namespace '%s':\n", ns)
+		output, err := sh.Output("kubectl", "get", "pods", "--namespace", ns)
+		if err != nil {
+			fmt.Printf("⚠️  Could not get pod status: %v\n", err)
+			continue
+		}
+		fmt.Printf("%s\n", output)
+	}
+
+	return nil
+}
+
+// ClusterAPI:ApplyWorkloadCluster reconciles workload cluster manifests
+// from clusters/workloads against the management cluster. This is kept
+// separate from clusters/providers, which holds the CAPI provider CRs
+// applied by ClusterAPI:Up.
+func (ClusterAPI) ApplyWorkloadCluster() error {
+	fmt.Printf("📦 Applying workload cluster manifests from '%s'...\n", workloadClustersDir)
+	if err := sh.Run("kubectl", "apply", "-f", workloadClustersDir, "--recursive"); err != nil {
+		return fmt.Errorf("failed to apply workload cluster manifests: %w", err)
+	}
+	fmt.Println("✅ Workload cluster manifests applied")
+	return nil
+}
+
+// PortForward:Start starts a detached port-forward to argocd-server on the
+// cluster currently selected by Cluster:Up (an empty context name means
+// the ambient kubeconfig current-context), so it survives this mage
+// invocation exiting.
+func (PortForward) Start() error {
+	mg.Deps(Cluster.Up)
+	fmt.Println("🔌 Starting port-forward to argocd-server...")
+
+	pid, err := internal.StartPortForward("", argocdServerService, argocdNS, argocdServerLocalPort, argocdServerAPIPort)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Port-forward started (pid %d); argocd-server is reachable at localhost:%d\n", pid, argocdServerLocalPort)
+	return nil
+}
+
+// PortForward:Stop stops the port-forward started by PortForward:Start.
+func (PortForward) Stop() error {
+	fmt.Println("🔥 Stopping port-forward to argocd-server...")
+
+	if err := internal.StopPortForward(argocdServerService, argocdNS, ""); err != nil {
+		return fmt.Errorf("failed to stop port-forward: %w", err)
+	}
+
+	fmt.Println("✅ Port-forward stopped")
+	return nil
+}
+
+// PortForward:Status reports whether the port-forward started by
+// PortForward:Start is still running.
+func (PortForward) Status() error {
+	fmt.Println("📊 Checking port-forward status...")
+
+	running, pid, err := internal.IsPortForwardRunning(argocdServerService, argocdNS, "")
+	if err != nil {
+		return fmt.Errorf("failed to check port-forward status: %w", err)
+	}
+
+	if !running {
+		fmt.Println("❌ Port-forward to argocd-server is not running")
+		return nil
+	}
+
+	fmt.Printf("✅ Port-forward to argocd-server is running (pid %d)\n", pid)
+	return nil
+}